@@ -26,6 +26,7 @@ import (
 
 	"github.com/Decentr-net/theseus/internal/consumer"
 	"github.com/Decentr-net/theseus/internal/consumer/blockchain"
+	"github.com/Decentr-net/theseus/internal/refresher"
 	"github.com/Decentr-net/theseus/internal/storage"
 	"github.com/Decentr-net/theseus/internal/storage/postgres"
 )
@@ -44,6 +45,13 @@ var opts = struct {
 	BlockchainTimeout                time.Duration `long:"blockchain.timeout" env:"BLOCKCHAIN_TIMEOUT" default:"5s" description:"timeout for requests to blockchain node"`
 	BlockchainRetryInterval          time.Duration `long:"blockchain.retry_interval" env:"BLOCKCHAIN_RETRY_INTERVAL" default:"2s" description:"interval to be waited on error before retry"`
 	BlockchainLastBlockRetryInterval time.Duration `long:"blockchain.last_block_retry_interval" env:"BLOCKCHAIN_LAST_BLOCK_RETRY_INTERVAL" default:"1s" description:"duration to be waited when new block isn't produced before retry"`
+	BlockchainFetchWindow            int           `long:"blockchain.fetch_window" env:"BLOCKCHAIN_FETCH_WINDOW" default:"10" description:"count of blocks fetched concurrently while catching up, blocks are committed in height order anyway, only the next block is polled at the chain head"`
+	BlockchainBatchSize              int           `long:"blockchain.batch_size" env:"BLOCKCHAIN_BATCH_SIZE" default:"100" description:"maximal count of blocks committed in one transaction while catching up the chain head"`
+
+	ViewsRefreshInterval time.Duration `long:"views.refresh_interval" env:"VIEWS_REFRESH_INTERVAL" default:"30s" description:"interval between refreshes of stats views, changes are coalesced into one refresh"`
+	ViewsMaxAge          time.Duration `long:"views.max_age" env:"VIEWS_MAX_AGE" default:"15m" description:"maximal age of stats views, they are refreshed even without changes when expired, because some of them depend on the current date"`
+
+	RelatedPostsRefreshInterval time.Duration `long:"related_posts.refresh_interval" env:"RELATED_POSTS_REFRESH_INTERVAL" default:"6h" description:"interval between refreshes of related posts, they are refreshed apart from stats views, because it's expensive"`
 
 	LogLevel  string `long:"log.level" env:"LOG_LEVEL" default:"info" description:"Log level" choice:"debug" choice:"info" choice:"warning" choice:"error"`
 	SentryDSN string `long:"sentry.dsn" env:"SENTRY_DSN" description:"sentry dsn"`
@@ -51,10 +59,45 @@ var opts = struct {
 
 var errTerminated = errors.New("terminated")
 
+// nolint:lll
+type rebuildCommand struct {
+	BatchSize uint64 `long:"batch-size" env:"REBUILD_BATCH_SIZE" default:"10000" description:"count of blocks which messages are read from the journal at once"`
+}
+
+func (c *rebuildCommand) Execute(_ []string) error {
+	s := postgres.New(mustGetDB())
+
+	logrus.Info("rebuilding derived tables")
+
+	if err := blockchain.Rebuild(context.Background(), s, c.BatchSize); err != nil {
+		return fmt.Errorf("failed to rebuild: %w", err)
+	}
+
+	logrus.Info("done")
+
+	return nil
+}
+
 func main() {
 	parser := flags.NewParser(&opts, flags.Default)
 	parser.ShortDescription = "Theseus Sync"
 	parser.LongDescription = "Theseus Sync"
+	parser.SubcommandsOptional = true
+
+	// commands are executed after logger and sentry initialization
+	var command flags.Commander
+	parser.CommandHandler = func(c flags.Commander, _ []string) error {
+		command = c
+		return nil
+	}
+
+	if _, err := parser.AddCommand("rebuild",
+		"Rebuild derived tables",
+		"Truncate derived tables and replay the stored messages journal up to the stored height",
+		&rebuildCommand{},
+	); err != nil {
+		logrus.WithError(err).Fatal("failed to add rebuild command")
+	}
 
 	_, err := parser.Parse()
 
@@ -90,15 +133,27 @@ func main() {
 		logrus.Warn("skip sentry initialization")
 	}
 
+	if command != nil {
+		if err := command.Execute(nil); err != nil {
+			logrus.WithError(err).Fatal("command failed")
+		}
+		return
+	}
+
 	db := mustGetDB()
 
 	s := postgres.New(db)
-	c := mustGetConsumer(s)
+	vr := refresher.New("views", s.RefreshViews, opts.ViewsRefreshInterval, opts.ViewsMaxAge)
+	// related posts are never marked dirty, so they are refreshed on start and when expired
+	rr := refresher.New("related_posts", s.RefreshRelatedPosts, opts.ViewsRefreshInterval, opts.RelatedPostsRefreshInterval)
+	c := mustGetConsumer(s, vr)
 
 	r := chi.NewMux()
 	r.Get("/health", health.Handler(
 		5*time.Second,
-		c, // consumer gets the height from db
+		c,  // consumer gets the height from db
+		vr, // refreshers return the last refresh time and lag
+		rr,
 	))
 	srv := http.Server{
 		Addr:    fmt.Sprintf("%s:%d", opts.Host, opts.Port),
@@ -113,6 +168,12 @@ func main() {
 	gr.Go(func() error {
 		return c.Run(ctx)
 	})
+	gr.Go(func() error {
+		return vr.Run(ctx)
+	})
+	gr.Go(func() error {
+		return rr.Run(ctx)
+	})
 	gr.Go(srv.ListenAndServe)
 	gr.Go(func() error {
 		sigs := make(chan os.Signal, 1)
@@ -177,11 +238,16 @@ func mustGetDB() *sql.DB {
 	return db
 }
 
-func mustGetConsumer(s storage.Storage) consumer.Consumer {
+func mustGetConsumer(s storage.Storage, r refresher.Refresher) consumer.Consumer {
 	fetcher, err := ariadne.New(context.Background(), opts.BlockchainNode, opts.BlockchainTimeout)
 	if err != nil {
 		logrus.WithError(err).Fatal("failed to create blocks fetcher")
 	}
 
-	return blockchain.New(fetcher, s, opts.BlockchainRetryInterval, opts.BlockchainLastBlockRetryInterval)
+	return blockchain.New(fetcher, s, r,
+		opts.BlockchainRetryInterval,
+		opts.BlockchainLastBlockRetryInterval,
+		opts.BlockchainFetchWindow,
+		opts.BlockchainBatchSize,
+	)
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -33,6 +34,8 @@ var opts = struct {
 	Port           int           `long:"http.port" env:"HTTP_PORT" default:"8080" description:"port to listen on for insecure connections, defaults to a random value"`
 	RequestTimeout time.Duration `long:"http.request-timeout" env:"HTTP_REQUEST_TIMEOUT" default:"45s" description:"request processing timeout"`
 
+	CursorSecret string `long:"cursor.secret" env:"CURSOR_SECRET" description:"secret used to sign pagination cursors, random one is generated when it's empty"`
+
 	Postgres                   string `long:"postgres" env:"POSTGRES" default:"host=localhost port=5432 user=postgres password=root sslmode=disable" description:"postgres dsn"`
 	PostgresMaxOpenConnections int    `long:"postgres.max_open_connections" env:"POSTGRES_MAX_OPEN_CONNECTIONS" default:"0" description:"postgres maximal open connections count, 0 means unlimited"`
 	PostgresMaxIdleConnections int    `long:"postgres.max_idle_connections" env:"POSTGRES_MAX_IDLE_CONNECTIONS" default:"5" description:"postgres maximal idle connections count"`
@@ -88,7 +91,7 @@ func main() {
 
 	s := postgres.New(db)
 
-	server.SetupRouter(s, r, opts.RequestTimeout)
+	server.SetupRouter(s, r, opts.RequestTimeout, mustGetCursorSecret())
 	r.Get("/health", health.Handler(
 		5*time.Second,
 		health.SubjectPinger("postgres", db.PingContext),
@@ -127,6 +130,21 @@ func main() {
 	}
 }
 
+func mustGetCursorSecret() []byte {
+	if opts.CursorSecret != "" {
+		return []byte(opts.CursorSecret)
+	}
+
+	logrus.Warn("empty cursor secret, cursors will be invalidated on restart and rejected by other instances")
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		logrus.WithError(err).Fatal("failed to generate cursor secret")
+	}
+
+	return b
+}
+
 func mustGetDB() *sql.DB {
 	db, err := sql.Open("postgres", opts.Postgres)
 	if err != nil {
@@ -10,6 +10,7 @@ require (
 	github.com/cosmos/cosmos-sdk v0.45.9
 	github.com/go-chi/chi v4.1.2+incompatible
 	github.com/go-chi/cors v1.1.1
+	github.com/gogo/protobuf v1.3.3
 	github.com/golang-migrate/migrate/v4 v4.12.2
 	github.com/golang/mock v1.6.0
 	github.com/jessevdk/go-flags v1.4.0
@@ -69,7 +70,6 @@ require (
 	github.com/godbus/dbus v0.0.0-20190726142602-4481cbc300e2 // indirect
 	github.com/gofrs/uuid v4.2.0+incompatible // indirect
 	github.com/gogo/gateway v1.1.0 // indirect
-	github.com/gogo/protobuf v1.3.3 // indirect
 	github.com/golang/groupcache v0.0.0-20210331224755-41bb18bfe9da // indirect
 	github.com/golang/protobuf v1.5.2 // indirect
 	github.com/golang/snappy v0.0.3 // indirect
@@ -7,40 +7,84 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/cosmos/cosmos-sdk/codec"
+	ctypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gogo/protobuf/proto"
 	"github.com/sirupsen/logrus"
 
 	"github.com/Decentr-net/ariadne"
 	"github.com/Decentr-net/decentr/config"
 	communitytypes "github.com/Decentr-net/decentr/x/community/types"
 	operationstypes "github.com/Decentr-net/decentr/x/operations/types"
+	tokentypes "github.com/Decentr-net/decentr/x/token/types"
 
 	"github.com/Decentr-net/theseus/internal/consumer"
+	"github.com/Decentr-net/theseus/internal/refresher"
 	"github.com/Decentr-net/theseus/internal/storage"
 )
 
 // nolint:gochecknoinits
 func init() {
 	config.SetAddressPrefixes()
+
+	sdk.RegisterInterfaces(registry)
+	communitytypes.RegisterInterfaces(registry)
+	operationstypes.RegisterInterfaces(registry)
+
+	// genesis states are saved to the messages journal by ImportGenesis
+	registry.RegisterInterface("theseus.blockchain.GenesisState", (*genesisState)(nil),
+		&communitytypes.GenesisState{},
+		&tokentypes.GenesisState{},
+	)
 }
 
-var log = logrus.WithField("package", "blockchain")
+// nolint:gochecknoglobals
+var (
+	log = logrus.WithField("package", "blockchain")
+
+	// registry contains messages of modules which are saved to the messages journal.
+	registry = ctypes.NewInterfaceRegistry()
+	cdc      = codec.NewProtoCodec(registry)
+)
+
+// catchUpThreshold is a distance to the chain head in blocks from which blocks are committed one by one.
+const catchUpThreshold = 5
 
 type blockchain struct {
 	f ariadne.Fetcher
 	s storage.Storage
+	r refresher.Refresher
 
 	retryInterval          time.Duration
 	retryLastBlockInterval time.Duration
+	fetchWindow            int
+	batchSize              int
 }
 
 // New returns new blockchain instance.
-func New(f ariadne.Fetcher, s storage.Storage, retryInterval, retryLastBlockInterval time.Duration) consumer.Consumer {
+// fetchWindow is count of blocks which are fetched concurrently while catching up, blocks are committed in height order anyway.
+// batchSize is maximal count of blocks committed in one transaction while catching up the chain head.
+// r is notified about every committed batch containing transactions.
+func New(f ariadne.Fetcher, s storage.Storage, r refresher.Refresher, retryInterval, retryLastBlockInterval time.Duration,
+	fetchWindow, batchSize int) consumer.Consumer {
+	if fetchWindow < 1 {
+		fetchWindow = 1
+	}
+
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
 	return blockchain{
 		f: f,
 		s: s,
+		r: r,
 
 		retryInterval:          retryInterval,
 		retryLastBlockInterval: retryLastBlockInterval,
+		fetchWindow:            fetchWindow,
+		batchSize:              batchSize,
 	}
 }
 
@@ -57,70 +101,181 @@ func (b blockchain) Ping(ctx context.Context) (interface{}, error) {
 }
 
 func (b blockchain) Run(ctx context.Context) error {
-	from, err := b.s.GetHeight(ctx)
+	h, err := b.s.GetHeight(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get current height: %w", err)
 	}
 
-	return b.f.FetchBlocks(ctx, from, b.processBlockFunc(ctx),
-		ariadne.WithErrHandler(logError),
-		ariadne.WithSkipError(false),
-		ariadne.WithRetryInterval(b.retryInterval),
-		ariadne.WithRetryLastBlockInterval(b.retryLastBlockInterval),
-	)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	processBlocks := b.processBlockFunc(ctx)
+
+	// the stored height is already processed
+	blocks := b.fetchBlocks(ctx, h+1)
+
+	for {
+		batch, ok := b.nextBatch(ctx, blocks)
+		if !ok {
+			return ctx.Err()
+		}
+
+		for {
+			err := processBlocks(batch...)
+			if err == nil {
+				break
+			}
+
+			logError(batch[0].Height, err)
+
+			if !sleep(ctx, b.retryInterval) {
+				return ctx.Err()
+			}
+		}
+	}
 }
 
-func (b blockchain) processBlockFunc(ctx context.Context) func(block ariadne.Block) error {
-	return func(block ariadne.Block) error {
-		return b.s.InTx(ctx, func(s storage.Storage) error {
-			log := log.WithField("height", block.Height).WithField("txs", len(block.Txs))
-			log.Info("processing block")
-			log.WithField("msgs", fmt.Sprintf("%+v", block.Messages())).Debug()
-
-			needRefreshPostsView := false
-			needRefreshStatsView := block.Height%50 == 0
-
-			for _, msg := range block.Messages() {
-				var err error
-
-				switch msg := msg.(type) {
-				case *communitytypes.MsgCreatePost:
-					needRefreshPostsView = true
-					err = processMsgCreatePost(ctx, s, block.Time, msg)
-				case *communitytypes.MsgDeletePost:
-					needRefreshPostsView = true
-					err = processMsgDeletePost(ctx, s, block.Time, *msg)
-				case *communitytypes.MsgSetLike:
-					needRefreshPostsView = true
-					needRefreshStatsView = true
-					err = processMsgSetLike(ctx, s, block.Time, *msg)
-				case *communitytypes.MsgFollow:
-					err = processMsgFollow(ctx, s, *msg)
-				case *communitytypes.MsgUnfollow:
-					err = processMsgUnfollow(ctx, s, *msg)
-				case *operationstypes.MsgDistributeRewards:
-					err = processDistributeRewards(ctx, s, block.Time, msg)
-				case *operationstypes.MsgResetAccount:
-					err = processMsgResetAccount(ctx, s, msg.Address)
-				default:
-					log.WithField("msg", msg).Debug("skip message")
-				}
+// nextBatch returns blocks to be committed in one transaction.
+// Blocks are batched only while catching up, otherwise they are committed one by one.
+func (b blockchain) nextBatch(ctx context.Context, blocks <-chan <-chan fetchedBlock) ([]ariadne.Block, bool) {
+	block, ok := receiveBlock(ctx, blocks)
+	if !ok {
+		return nil, false
+	}
+
+	batch := []ariadne.Block{block.Block}
+	for len(batch) < b.batchSize && block.isCatchingUp() {
+		if block, ok = receiveBlock(ctx, blocks); !ok {
+			return nil, false
+		}
+
+		batch = append(batch, block.Block)
+	}
+
+	return batch, true
+}
 
-				if err != nil {
-					return fmt.Errorf("failed to process msg: %w", err)
+func (b blockchain) processBlockFunc(ctx context.Context) func(blocks ...ariadne.Block) error {
+	return func(blocks ...ariadne.Block) error {
+		if err := b.s.InTx(ctx, func(s storage.Storage) error {
+			for _, block := range blocks {
+				log := log.WithField("height", block.Height).WithField("txs", len(block.Txs))
+				log.Info("processing block")
+				log.WithField("msgs", fmt.Sprintf("%+v", block.Messages())).Debug()
+
+				if err := processBlock(ctx, s, block); err != nil {
+					return err
 				}
 			}
 
-			if err := s.SetHeight(ctx, block.Height); err != nil {
-				return fmt.Errorf("failed to set height: %w", err)
+			if err := s.AddJournalRange(ctx, blocks[0].Height, blocks[len(blocks)-1].Height); err != nil {
+				return fmt.Errorf("failed to add journal range: %w", err)
 			}
 
-			if err := s.RefreshViews(ctx, needRefreshPostsView, needRefreshStatsView); err != nil {
-				return fmt.Errorf("failed to refresh views: %w", err)
+			if err := s.SetHeight(ctx, blocks[len(blocks)-1].Height); err != nil {
+				return fmt.Errorf("failed to set height: %w", err)
 			}
 
 			return nil
-		})
+		}); err != nil {
+			return err
+		}
+
+		// views are refreshed in background to not stall blocks processing
+		for _, block := range blocks {
+			if len(block.Txs) > 0 {
+				b.r.MarkDirty()
+				break
+			}
+		}
+
+		return nil
+	}
+}
+
+// processBlock saves block messages to the journal and processes them.
+func processBlock(ctx context.Context, s storage.Storage, block ariadne.Block) error {
+	if err := saveMessages(ctx, s, block); err != nil {
+		return err
+	}
+
+	for _, msg := range block.Messages() {
+		if err := processMsg(ctx, s, block.Height, block.Time, msg); err != nil {
+			return fmt.Errorf("failed to process msg: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// saveMessages saves messages of the block to the messages journal.
+func saveMessages(ctx context.Context, s storage.Storage, block ariadne.Block) error {
+	for txIndex, tx := range block.Txs {
+		for msgIndex, msg := range tx.GetMsgs() {
+			if err := saveMessage(ctx, s, block, uint32(txIndex), uint32(msgIndex), msg); err != nil {
+				return fmt.Errorf("failed to save msg: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func saveMessage(ctx context.Context, s storage.Storage, block ariadne.Block, txIndex, msgIndex uint32, msg sdk.Msg) error {
+	typeURL := sdk.MsgTypeURL(msg)
+
+	// skip messages of modules which are not consumed
+	if _, err := registry.Resolve(typeURL); err != nil {
+		return nil
+	}
+
+	m, err := newMessage(block.Height, block.Time, txIndex, msgIndex, msg)
+	if err != nil {
+		return err
+	}
+
+	return s.AddMessage(ctx, m)
+}
+
+func newMessage(height uint64, timestamp time.Time, txIndex, msgIndex uint32, msg proto.Message) (*storage.Message, error) {
+	payload, err := cdc.MarshalJSON(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal: %w", err)
+	}
+
+	return &storage.Message{
+		Height:   height,
+		Time:     timestamp,
+		TxIndex:  txIndex,
+		MsgIndex: msgIndex,
+		Type:     "/" + proto.MessageName(msg),
+		Payload:  payload,
+	}, nil
+}
+
+func processMsg(ctx context.Context, s storage.Storage, height uint64, timestamp time.Time, msg sdk.Msg) error {
+	switch msg := msg.(type) {
+	case *communitytypes.MsgCreatePost:
+		return processMsgCreatePost(ctx, s, timestamp, msg)
+	case *communitytypes.MsgDeletePost:
+		return processMsgDeletePost(ctx, s, timestamp, *msg)
+	case *communitytypes.MsgSetLike:
+		return processMsgSetLike(ctx, s, timestamp, *msg)
+	case *communitytypes.MsgFollow:
+		return processMsgFollow(ctx, s, height, timestamp, *msg)
+	case *communitytypes.MsgUnfollow:
+		return processMsgUnfollow(ctx, s, height, timestamp, *msg)
+	case *operationstypes.MsgDistributeRewards:
+		return processDistributeRewards(ctx, s, timestamp, msg)
+	case *operationstypes.MsgResetAccount:
+		return processMsgResetAccount(ctx, s, msg.Address)
+	case *operationstypes.MsgMint:
+		return processTokenMovement(ctx, s, timestamp, storage.MintTokenMovementType, msg.Owner, msg.Coin)
+	case *operationstypes.MsgBurn:
+		return processTokenMovement(ctx, s, timestamp, storage.BurnTokenMovementType, msg.Owner, msg.Coin)
+	default:
+		log.WithField("msg", msg).Debug("skip message")
+		return nil
 	}
 }
 
@@ -161,7 +316,14 @@ func processMsgSetLike(ctx context.Context, s storage.Storage, timestamp time.Ti
 		previousWeight = l
 	}
 
-	if err := s.AddPDV(ctx, msg.Like.PostOwner, int64(msg.Like.Weight-previousWeight), timestamp); err != nil {
+	if err := s.AddPDV(ctx, &storage.PDV{
+		Address:      msg.Like.PostOwner,
+		Amount:       int64(msg.Like.Weight - previousWeight),
+		Source:       storage.LikePDVSource,
+		Post:         &p,
+		Counterparty: msg.Like.Owner,
+		Timestamp:    timestamp,
+	}); err != nil {
 		return fmt.Errorf("failed to add pdv to profile stats: %w", err)
 	}
 
@@ -179,17 +341,23 @@ func processMsgSetLike(ctx context.Context, s storage.Storage, timestamp time.Ti
 	return s.SetLike(ctx, postID, msg.Like.Weight, timestamp, msg.Like.Owner)
 }
 
-func processMsgFollow(ctx context.Context, s storage.Storage, msg communitytypes.MsgFollow) error {
-	return s.Follow(ctx, msg.Owner, msg.Whom)
+func processMsgFollow(ctx context.Context, s storage.Storage, height uint64, timestamp time.Time, msg communitytypes.MsgFollow) error {
+	return s.Follow(ctx, msg.Owner, msg.Whom, height, timestamp)
 }
 
-func processMsgUnfollow(ctx context.Context, s storage.Storage, msg communitytypes.MsgUnfollow) error {
-	return s.Unfollow(ctx, msg.Owner, msg.Whom)
+func processMsgUnfollow(ctx context.Context, s storage.Storage, height uint64, timestamp time.Time, msg communitytypes.MsgUnfollow) error {
+	return s.Unfollow(ctx, msg.Owner, msg.Whom, height, timestamp)
 }
 
 func processDistributeRewards(ctx context.Context, s storage.Storage, timestamp time.Time, msg *operationstypes.MsgDistributeRewards) error {
 	for _, v := range msg.Rewards {
-		if err := s.AddPDV(ctx, v.Receiver, v.Reward.Dec.MulInt64(storage.PDVDenominator).TruncateInt64(), timestamp); err != nil {
+		if err := s.AddPDV(ctx, &storage.PDV{
+			Address:      v.Receiver,
+			Amount:       v.Reward.Dec.MulInt64(storage.PDVDenominator).TruncateInt64(),
+			Source:       storage.RewardPDVSource,
+			Counterparty: msg.Owner,
+			Timestamp:    timestamp,
+		}); err != nil {
 			return fmt.Errorf("failed to add pdv: %w", err)
 		}
 	}
@@ -204,3 +372,23 @@ func processMsgResetAccount(ctx context.Context, s storage.Storage, owner string
 
 	return nil
 }
+
+func processTokenMovement(ctx context.Context, s storage.Storage, timestamp time.Time,
+	t storage.TokenMovementType, address string, coin sdk.Coin) error {
+	// amounts are stored as BIGINT, so an overflow stops processing instead of corrupting balances
+	if !coin.Amount.IsInt64() {
+		return fmt.Errorf("%s amount %s overflows int64", t, coin) //nolint:goerr113
+	}
+
+	if err := s.AddTokenMovement(ctx, &storage.TokenMovement{
+		Type:      t,
+		Address:   address,
+		Denom:     coin.Denom,
+		Amount:    coin.Amount.Int64(),
+		Timestamp: timestamp,
+	}); err != nil {
+		return fmt.Errorf("failed to add token movement: %w", err)
+	}
+
+	return nil
+}
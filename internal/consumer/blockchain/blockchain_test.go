@@ -3,12 +3,15 @@ package blockchain
 import (
 	"context"
 	"errors"
+	"math"
+	"sync"
 	"testing"
 	"time"
 
 	ctypes "github.com/cosmos/cosmos-sdk/codec/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/tx"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 
@@ -17,6 +20,7 @@ import (
 	communitytypes "github.com/Decentr-net/decentr/x/community/types"
 	operationstypes "github.com/Decentr-net/decentr/x/operations/types"
 
+	refreshermock "github.com/Decentr-net/theseus/internal/refresher/mock"
 	"github.com/Decentr-net/theseus/internal/storage"
 	storagemock "github.com/Decentr-net/theseus/internal/storage/mock"
 )
@@ -28,27 +32,164 @@ func TestBlockchain_Run(t *testing.T) {
 
 	f, s := ariadnemock.NewMockFetcher(ctrl), storagemock.NewMockStorage(ctrl)
 
-	b := New(f, s, time.Nanosecond, time.Nanosecond)
+	b := New(f, s, nil, time.Nanosecond, time.Nanosecond, 3, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
 	s.EXPECT().GetHeight(gomock.Any()).Return(uint64(1), nil)
 
-	f.EXPECT().FetchBlocks(gomock.Any(), uint64(1), gomock.Any(), gomock.Any()).Return(nil)
+	// the latest block, it's requested when the first block is fetched at once
+	f.EXPECT().FetchBlock(gomock.Any(), uint64(0)).Return(&ariadne.Block{Height: 5}, nil)
+	f.EXPECT().FetchBlock(gomock.Any(), uint64(3)).Return(nil, errTest)
+	// earlier blocks are fetched slower
+	f.EXPECT().FetchBlock(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, h uint64) (*ariadne.Block, error) {
+		if h > 5 {
+			return nil, ariadne.ErrTooHighBlockRequested
+		}
+		time.Sleep(time.Duration(6-h) * time.Millisecond)
+		return &ariadne.Block{Height: h}, nil
+	}).AnyTimes()
+
+	s.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(_ storage.Storage) error) error {
+		return f(s)
+	}).AnyTimes()
+
+	s.EXPECT().AddJournalRange(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	var heights []uint64
+	s.EXPECT().SetHeight(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, h uint64) error {
+		heights = append(heights, h)
+		if h == 5 {
+			cancel()
+		}
+		return nil
+	}).Times(4)
 
-	require.NoError(t, b.Run(context.Background()))
+	require.ErrorIs(t, b.Run(ctx), context.Canceled)
+	require.Equal(t, []uint64{2, 3, 4, 5}, heights)
 }
 
-func TestBlockchain_Run_Error(t *testing.T) {
+func TestBlockchain_Run_Head(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	f, s := ariadnemock.NewMockFetcher(ctrl), storagemock.NewMockStorage(ctrl)
+
+	b := New(f, s, nil, time.Nanosecond, time.Nanosecond, 10, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.EXPECT().GetHeight(gomock.Any()).Return(uint64(1), nil)
+
+	var (
+		mu       sync.Mutex
+		polls    int
+		requests []uint64
+	)
+	f.EXPECT().FetchBlock(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, h uint64) (*ariadne.Block, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		requests = append(requests, h)
+		if h < 3 {
+			return &ariadne.Block{Height: h}, nil
+		}
+
+		if polls++; polls == 5 {
+			cancel()
+		}
+		return nil, ariadne.ErrTooHighBlockRequested
+	}).AnyTimes()
+
+	s.EXPECT().InTx(gomock.Any(), gomock.Any()).Return(nil)
+
+	require.ErrorIs(t, b.Run(ctx), context.Canceled)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// the head is reached at the block 2, so only the next block is polled
+	require.Equal(t, []uint64{2, 0, 3, 3, 3, 3, 3}, requests)
+}
+
+func TestBlockchain_Run_RetryProcessing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	f, s := ariadnemock.NewMockFetcher(ctrl), storagemock.NewMockStorage(ctrl)
+
+	b := New(f, s, nil, time.Nanosecond, time.Nanosecond, 1, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.EXPECT().GetHeight(gomock.Any()).Return(uint64(1), nil)
+
+	f.EXPECT().FetchBlock(gomock.Any(), uint64(2)).Return(&ariadne.Block{Height: 2}, nil)
+	f.EXPECT().FetchBlock(gomock.Any(), gomock.Any()).Return(nil, ariadne.ErrTooHighBlockRequested).AnyTimes()
+
+	gomock.InOrder(
+		s.EXPECT().InTx(gomock.Any(), gomock.Any()).Return(errTest),
+		s.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, func(_ storage.Storage) error) error {
+			cancel()
+			return nil
+		}),
+	)
+
+	require.ErrorIs(t, b.Run(ctx), context.Canceled)
+}
+
+func TestBlockchain_Run_Batch(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
 	f, s := ariadnemock.NewMockFetcher(ctrl), storagemock.NewMockStorage(ctrl)
 
-	b := New(f, s, time.Nanosecond, time.Nanosecond)
+	b := New(f, s, nil, time.Nanosecond, time.Nanosecond, 3, 4)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
 	s.EXPECT().GetHeight(gomock.Any()).Return(uint64(1), nil)
 
-	f.EXPECT().FetchBlocks(gomock.Any(), uint64(1), gomock.Any(), gomock.Any()).Return(errTest)
+	// the latest block is requested once, the known head is used until it's reached
+	f.EXPECT().FetchBlock(gomock.Any(), uint64(0)).Return(&ariadne.Block{Height: 20}, nil)
+	f.EXPECT().FetchBlock(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, h uint64) (*ariadne.Block, error) {
+		if h > 20 {
+			return nil, ariadne.ErrTooHighBlockRequested
+		}
+		return &ariadne.Block{Height: h}, nil
+	}).AnyTimes()
+
+	s.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(_ storage.Storage) error) error {
+		return f(s)
+	}).Times(6)
+
+	s.EXPECT().AddJournalRange(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	var heights []uint64
+	s.EXPECT().SetHeight(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, h uint64) error {
+		heights = append(heights, h)
+		if h == 17 {
+			cancel()
+		}
+		return nil
+	}).Times(6)
+
+	require.ErrorIs(t, b.Run(ctx), context.Canceled)
+	// blocks closer than catchUpThreshold to the head are committed one by one
+	require.Equal(t, []uint64{5, 9, 13, 15, 16, 17}, heights)
+}
+
+func TestBlockchain_Run_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	f, s := ariadnemock.NewMockFetcher(ctrl), storagemock.NewMockStorage(ctrl)
+
+	b := New(f, s, nil, time.Nanosecond, time.Nanosecond, 1, 1)
+
+	s.EXPECT().GetHeight(gomock.Any()).Return(uint64(0), errTest)
 
-	require.Equal(t, errTest, b.Run(context.Background()))
+	require.ErrorIs(t, b.Run(context.Background()), errTest)
 }
 
 func TestBlockchain_processBlockFunc(t *testing.T) {
@@ -110,7 +251,14 @@ func TestBlockchain_processBlockFunc(t *testing.T) {
 					}: communitytypes.LikeWeight_LIKE_WEIGHT_UP,
 				}, nil)
 
-				s.EXPECT().AddPDV(gomock.Any(), owner.String(), int64(-2), timestamp).Return(nil)
+				s.EXPECT().AddPDV(gomock.Any(), &storage.PDV{
+					Address:      owner.String(),
+					Amount:       -2,
+					Source:       storage.LikePDVSource,
+					Post:         &storage.PostID{Owner: owner.String(), UUID: "1234"},
+					Counterparty: owner.String(),
+					Timestamp:    timestamp,
+				}).Return(nil)
 
 				s.EXPECT().GetPost(gomock.Any(), storage.PostID{Owner: "decentr1u9slwz3sje8j94ccpwlslflg0506yc8y2ylmtz", UUID: "1234"}).Return(&storage.Post{}, nil)
 
@@ -145,7 +293,7 @@ func TestBlockchain_processBlockFunc(t *testing.T) {
 				Whom:  owner2.String(),
 			},
 			expect: func(s *storagemock.MockStorage) {
-				s.EXPECT().Follow(gomock.Any(), owner.String(), owner2.String())
+				s.EXPECT().Follow(gomock.Any(), owner.String(), owner2.String(), uint64(1), timestamp)
 			},
 		},
 		{
@@ -155,7 +303,7 @@ func TestBlockchain_processBlockFunc(t *testing.T) {
 				Whom:  owner2.String(),
 			},
 			expect: func(s *storagemock.MockStorage) {
-				s.EXPECT().Unfollow(gomock.Any(), owner.String(), owner2.String())
+				s.EXPECT().Unfollow(gomock.Any(), owner.String(), owner2.String(), uint64(1), timestamp)
 			},
 		},
 		{
@@ -174,8 +322,20 @@ func TestBlockchain_processBlockFunc(t *testing.T) {
 				},
 			},
 			expect: func(s *storagemock.MockStorage) {
-				s.EXPECT().AddPDV(gomock.Any(), owner.String(), int64(100), timestamp)
-				s.EXPECT().AddPDV(gomock.Any(), owner2.String(), int64(10), timestamp)
+				s.EXPECT().AddPDV(gomock.Any(), &storage.PDV{
+					Address:      owner.String(),
+					Amount:       100,
+					Source:       storage.RewardPDVSource,
+					Counterparty: owner.String(),
+					Timestamp:    timestamp,
+				})
+				s.EXPECT().AddPDV(gomock.Any(), &storage.PDV{
+					Address:      owner2.String(),
+					Amount:       10,
+					Source:       storage.RewardPDVSource,
+					Counterparty: owner.String(),
+					Timestamp:    timestamp,
+				})
 			},
 		},
 		{
@@ -188,6 +348,38 @@ func TestBlockchain_processBlockFunc(t *testing.T) {
 				s.EXPECT().ResetAccount(gomock.Any(), owner.String())
 			},
 		},
+		{
+			name: "mint",
+			msg: &operationstypes.MsgMint{
+				Owner: owner.String(),
+				Coin:  sdk.NewInt64Coin("udec", 100),
+			},
+			expect: func(s *storagemock.MockStorage) {
+				s.EXPECT().AddTokenMovement(gomock.Any(), &storage.TokenMovement{
+					Type:      storage.MintTokenMovementType,
+					Address:   owner.String(),
+					Denom:     "udec",
+					Amount:    100,
+					Timestamp: timestamp,
+				})
+			},
+		},
+		{
+			name: "burn",
+			msg: &operationstypes.MsgBurn{
+				Owner: owner.String(),
+				Coin:  sdk.NewInt64Coin("udec", 10),
+			},
+			expect: func(s *storagemock.MockStorage) {
+				s.EXPECT().AddTokenMovement(gomock.Any(), &storage.TokenMovement{
+					Type:      storage.BurnTokenMovementType,
+					Address:   owner.String(),
+					Denom:     "udec",
+					Amount:    10,
+					Timestamp: timestamp,
+				})
+			},
+		},
 	}
 
 	for i := range tt {
@@ -195,13 +387,26 @@ func TestBlockchain_processBlockFunc(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			s := storagemock.NewMockStorage(gomock.NewController(t))
+			ctrl := gomock.NewController(t)
+			s, r := storagemock.NewMockStorage(ctrl), refreshermock.NewMockRefresher(ctrl)
 
 			s.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(_ storage.Storage) error) error {
 				return f(s)
 			})
+			s.EXPECT().AddJournalRange(gomock.Any(), uint64(1), uint64(1)).Return(nil)
 			s.EXPECT().SetHeight(gomock.Any(), uint64(1)).Return(nil)
-			s.EXPECT().RefreshViews(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			r.EXPECT().MarkDirty()
+
+			payload, err := cdc.MarshalJSON(tc.msg)
+			require.NoError(t, err)
+			s.EXPECT().AddMessage(gomock.Any(), &storage.Message{
+				Height:   1,
+				Time:     timestamp,
+				TxIndex:  0,
+				MsgIndex: 0,
+				Type:     sdk.MsgTypeURL(tc.msg),
+				Payload:  payload,
+			}).Return(nil)
 			tc.expect(s)
 
 			msg, err := ctypes.NewAnyWithValue(tc.msg)
@@ -220,17 +425,76 @@ func TestBlockchain_processBlockFunc(t *testing.T) {
 				},
 			}
 
-			require.NoError(t, blockchain{s: s}.processBlockFunc(context.Background())(block))
+			require.NoError(t, blockchain{s: s, r: r}.processBlockFunc(context.Background())(block))
 		})
 	}
 }
 
+func TestBlockchain_processBlockFunc_skipMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	s, r := storagemock.NewMockStorage(ctrl), refreshermock.NewMockRefresher(ctrl)
+
+	s.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(_ storage.Storage) error) error {
+		return f(s)
+	})
+	s.EXPECT().AddJournalRange(gomock.Any(), uint64(1), uint64(1)).Return(nil)
+	s.EXPECT().SetHeight(gomock.Any(), uint64(1)).Return(nil)
+	r.EXPECT().MarkDirty()
+
+	msg, err := ctypes.NewAnyWithValue(&banktypes.MsgSend{})
+	require.NoError(t, err)
+
+	require.NoError(t, blockchain{s: s, r: r}.processBlockFunc(context.Background())(ariadne.Block{
+		Height: 1,
+		Txs: []sdk.Tx{
+			&tx.Tx{
+				Body: &tx.TxBody{
+					Messages: []*ctypes.Any{
+						msg,
+					},
+				},
+			},
+		},
+	}))
+}
+
 func TestBlockchain_processBlockFunc_errors(t *testing.T) {
-	s := storagemock.NewMockStorage(gomock.NewController(t))
+	ctrl := gomock.NewController(t)
+	s, r := storagemock.NewMockStorage(ctrl), refreshermock.NewMockRefresher(ctrl)
 
 	s.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(_ storage.Storage) error) error {
 		return context.Canceled
 	})
 
-	require.Error(t, blockchain{s: s}.processBlockFunc(context.Background())(ariadne.Block{Height: 1}))
+	// views are not marked dirty by failed transaction
+	require.Error(t, blockchain{s: s, r: r}.processBlockFunc(context.Background())(ariadne.Block{
+		Height: 1,
+		Txs:    []sdk.Tx{&tx.Tx{Body: &tx.TxBody{}}},
+	}))
+}
+
+func TestProcessTokenMovement_Overflow(t *testing.T) {
+	s := storagemock.NewMockStorage(gomock.NewController(t))
+
+	coin := sdk.NewCoin("udec", sdk.NewIntFromUint64(math.MaxUint64))
+	require.EqualError(t,
+		processTokenMovement(context.Background(), s, time.Now(), storage.MintTokenMovementType, "address", coin),
+		"mint amount 18446744073709551615udec overflows int64",
+	)
+}
+
+func TestBlockchain_processBlockFunc_emptyBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	s, r := storagemock.NewMockStorage(ctrl), refreshermock.NewMockRefresher(ctrl)
+
+	s.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(_ storage.Storage) error) error {
+		return f(s)
+	})
+	s.EXPECT().AddJournalRange(gomock.Any(), uint64(1), uint64(2)).Return(nil)
+	s.EXPECT().SetHeight(gomock.Any(), uint64(2)).Return(nil)
+
+	// views are not marked dirty by blocks without transactions
+	require.NoError(t, blockchain{s: s, r: r}.processBlockFunc(context.Background())(
+		ariadne.Block{Height: 1}, ariadne.Block{Height: 2},
+	))
 }
@@ -0,0 +1,150 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Decentr-net/ariadne"
+)
+
+// fetchedBlock is a block with the latest height of the chain known when the block is fetched.
+type fetchedBlock struct {
+	ariadne.Block
+	head uint64
+}
+
+// isCatchingUp returns true when the chain head is farther than catchUpThreshold blocks from the block.
+func (b fetchedBlock) isCatchingUp() bool {
+	return b.Height+catchUpThreshold < b.head
+}
+
+// fetchBlocks fetches blocks starting from the height with fetchWindow concurrent requests.
+// Every block is delivered through its own channel and channels are returned in height order,
+// so blocks can be committed in order while next ones are being fetched.
+// Only blocks up to the latest known height are fetched concurrently, the next one could be not produced yet,
+// so it's polled alone until it's produced, and the latest height is refreshed only then.
+// Block channel is closed without a block when ctx is done.
+func (b blockchain) fetchBlocks(ctx context.Context, from uint64) <-chan <-chan fetchedBlock {
+	// buffer size limits count of blocks which are being fetched or wait for commit
+	out := make(chan (<-chan fetchedBlock), b.fetchWindow-1)
+
+	go func() {
+		defer close(out)
+
+		var head uint64 // the latest known height of the chain
+		for height := from; ; height++ {
+			block := make(chan fetchedBlock, 1)
+
+			select {
+			case <-ctx.Done():
+				return
+			case out <- block:
+			}
+
+			if height <= head {
+				go func(height, head uint64) {
+					defer close(block)
+
+					if v, _ := b.fetchBlock(ctx, height); v != nil {
+						block <- fetchedBlock{Block: *v, head: head}
+					}
+				}(height, head)
+
+				continue
+			}
+
+			v, reached := b.fetchBlock(ctx, height)
+			if v == nil {
+				close(block)
+				return
+			}
+
+			head = height
+			if !reached {
+				// the block was already produced, so the chain could be ahead
+				head = b.fetchHead(ctx, height)
+			}
+
+			block <- fetchedBlock{Block: *v, head: head}
+			close(block)
+		}
+	}()
+
+	return out
+}
+
+// receiveBlock receives the next block from fetchBlocks output. It returns false when ctx is done.
+func receiveBlock(ctx context.Context, blocks <-chan <-chan fetchedBlock) (fetchedBlock, bool) {
+	// already fetched blocks are still available after ctx is done
+	if ctx.Err() != nil {
+		return fetchedBlock{}, false
+	}
+
+	next, ok := <-blocks
+	if !ok {
+		return fetchedBlock{}, false
+	}
+
+	block, ok := <-next
+	return block, ok
+}
+
+// fetchBlock fetches the block. It retries until success or ctx is done, nil is returned when ctx is done.
+// reached is true when the block wasn't produced at the first request, i.e. it's the chain head.
+func (b blockchain) fetchBlock(ctx context.Context, height uint64) (*ariadne.Block, bool) {
+	var reached bool
+	for {
+		block, err := b.f.FetchBlock(ctx, height)
+		if err == nil {
+			return block, reached
+		}
+
+		if ctx.Err() != nil {
+			return nil, false
+		}
+
+		wait := b.retryInterval
+		if errors.Is(err, ariadne.ErrTooHighBlockRequested) {
+			reached = true
+			wait = b.retryLastBlockInterval
+		} else {
+			logError(height, fmt.Errorf("failed to get block: %w", err))
+		}
+
+		if !sleep(ctx, wait) {
+			return nil, false
+		}
+	}
+}
+
+// fetchHead returns the latest height of the chain. The known height is returned on error.
+func (b blockchain) fetchHead(ctx context.Context, known uint64) uint64 {
+	latest, err := b.f.FetchBlock(ctx, 0)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.WithError(err).Error("failed to get the latest block")
+		}
+		return known
+	}
+
+	if latest.Height < known {
+		return known
+	}
+
+	return latest.Height
+}
+
+// sleep pauses for the duration. It returns false if ctx is done earlier.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
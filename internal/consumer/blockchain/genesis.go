@@ -0,0 +1,124 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gogo/protobuf/proto"
+
+	communitytypes "github.com/Decentr-net/decentr/x/community/types"
+	tokentypes "github.com/Decentr-net/decentr/x/token/types"
+
+	"github.com/Decentr-net/theseus/internal/storage"
+)
+
+// genesisState is implemented by genesis states saved to the messages journal.
+type genesisState interface {
+	proto.Message
+}
+
+// ImportGenesis saves token and community genesis states to the messages journal as messages of zero height
+// and imports them, so they will be replayed by Rebuild.
+func ImportGenesis(ctx context.Context, s storage.Storage, timestamp time.Time,
+	token *tokentypes.GenesisState, community *communitytypes.GenesisState) error {
+	for i, v := range []genesisState{token, community} {
+		m, err := newMessage(0, timestamp, 0, uint32(i), v)
+		if err != nil {
+			return err
+		}
+
+		if err := s.AddMessage(ctx, m); err != nil {
+			return fmt.Errorf("failed to save genesis: %w", err)
+		}
+	}
+
+	if err := s.AddJournalRange(ctx, 0, 0); err != nil {
+		return fmt.Errorf("failed to add journal range: %w", err)
+	}
+
+	if err := importTokenGenesis(ctx, s, timestamp, token); err != nil {
+		return fmt.Errorf("failed to import token genesis: %w", err)
+	}
+
+	if err := importCommunityGenesis(ctx, s, timestamp, community); err != nil {
+		return fmt.Errorf("failed to import community genesis: %w", err)
+	}
+
+	return nil
+}
+
+func importTokenGenesis(ctx context.Context, s storage.Storage, timestamp time.Time, g *tokentypes.GenesisState) error {
+	log.Info("import token")
+	i := 0
+	for k, v := range g.Balances {
+		if err := s.AddPDV(ctx, &storage.PDV{
+			Address:   k,
+			Amount:    v.Dec.Sub(sdk.OneDec()).TruncateInt64() * storage.PDVDenominator,
+			Source:    storage.GenesisPDVSource,
+			Timestamp: timestamp,
+		}); err != nil {
+			return fmt.Errorf("failed to add pdv: %w", err)
+		}
+
+		i++
+		if i%20 == 0 {
+			log.Infof("%d of %d balances imported", i, len(g.Balances))
+		}
+	}
+
+	return nil
+}
+
+func importCommunityGenesis(ctx context.Context, s storage.Storage, timestamp time.Time, g *communitytypes.GenesisState) error {
+	log.Info("import followings")
+	i := 0
+	for follower, v := range g.Following {
+		for _, followee := range v.Address {
+			if err := s.Follow(ctx, follower, followee, 0, timestamp); err != nil {
+				return fmt.Errorf("failed to follow: %w", err)
+			}
+		}
+
+		i++
+		if i%20 == 0 {
+			log.Infof("%d of %d followers imported", i, len(g.Following))
+		}
+	}
+
+	log.Info("import posts")
+	for i, v := range g.Posts {
+		if err := s.CreatePost(ctx, &storage.CreatePostParams{
+			UUID:         v.Uuid,
+			Owner:        v.Owner,
+			Title:        v.Title,
+			Category:     v.Category,
+			PreviewImage: v.PreviewImage,
+			Text:         v.Text,
+			CreatedAt:    timestamp,
+		}); err != nil {
+			return fmt.Errorf("failed to create post: %w", err)
+		}
+
+		if (i+1)%20 == 0 {
+			log.Infof("%d of %d posts imported", i+1, len(g.Posts))
+		}
+	}
+
+	log.Info("import likes")
+	for i, v := range g.Likes {
+		if err := s.SetLike(ctx, storage.PostID{
+			Owner: v.PostOwner,
+			UUID:  v.PostUuid,
+		}, v.Weight, timestamp, v.Owner); err != nil {
+			return fmt.Errorf("failed to set like: %w", err)
+		}
+
+		if (i+1)%20 == 0 {
+			log.Infof("%d of %d likes imported", i+1, len(g.Likes))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gogo/protobuf/proto"
+
+	communitytypes "github.com/Decentr-net/decentr/x/community/types"
+	tokentypes "github.com/Decentr-net/decentr/x/token/types"
+
+	"github.com/Decentr-net/theseus/internal/storage"
+)
+
+var (
+	// ErrInvalidBatchSize is returned when the batch size is zero.
+	ErrInvalidBatchSize = errors.New("batch size should be greater than zero")
+	// ErrIncompleteJournal is returned when the messages journal doesn't cover genesis and all processed blocks.
+	ErrIncompleteJournal = errors.New("messages journal is incomplete")
+)
+
+// Rebuild truncates derived tables and replays the messages journal up to the stored height.
+// The journal is read by batches of batchSize blocks. Everything is done in one transaction.
+// ErrIncompleteJournal is returned when the journal doesn't cover genesis and all processed blocks,
+// e.g. when blocks were processed before the journal was introduced; such database should be synced from scratch.
+// There is no rebuild of a heights range, because some messages (e.g. MsgResetAccount) can't be rolled back,
+// so data damaged by a handler bug is fixed by the full rebuild after the handler is fixed.
+func Rebuild(ctx context.Context, s storage.Storage, batchSize uint64) error {
+	if batchSize == 0 {
+		return ErrInvalidBatchSize
+	}
+
+	return s.InTx(ctx, func(s storage.Storage) error {
+		height, err := truncateDerived(ctx, s)
+		if err != nil {
+			return err
+		}
+
+		if err := replay(ctx, s, 0, height, batchSize); err != nil {
+			return err
+		}
+
+		return refreshViews(ctx, s)
+	})
+}
+
+// truncateDerived checks the messages journal covers the stored height and truncates derived tables.
+// It returns the stored height to be replayed up to.
+func truncateDerived(ctx context.Context, s storage.Storage) (uint64, error) {
+	height, err := s.GetHeight(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get height: %w", err)
+	}
+
+	if err := checkJournal(ctx, s, height); err != nil {
+		return 0, err
+	}
+
+	// truncate locks derived tables until commit, so blocks processed by a running sync are either
+	// replayed below or wait for the rebuild; they are journaled along with the height, so the check holds for them
+	if err := s.TruncateDerived(ctx); err != nil {
+		return 0, fmt.Errorf("failed to truncate derived tables: %w", err)
+	}
+
+	if height, err = s.GetHeight(ctx); err != nil {
+		return 0, fmt.Errorf("failed to get height: %w", err)
+	}
+
+	return height, nil
+}
+
+// checkJournal returns ErrIncompleteJournal unless the journal has genesis and all blocks up to height.
+func checkJournal(ctx context.Context, s storage.Storage, height uint64) error {
+	ranges, err := s.ListJournalRanges(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list journal ranges: %w", err)
+	}
+
+	// ranges are merged, so the history is covered by the first one
+	if len(ranges) == 0 || ranges[0].From != 0 {
+		return fmt.Errorf("%w: genesis is missing", ErrIncompleteJournal)
+	}
+
+	if ranges[0].To < height {
+		missing := height
+		if len(ranges) > 1 && ranges[1].From <= height {
+			missing = ranges[1].From - 1
+		}
+
+		return fmt.Errorf("%w: blocks %d-%d are missing", ErrIncompleteJournal, ranges[0].To+1, missing)
+	}
+
+	return nil
+}
+
+// replay processes journaled messages of [from, to] blocks.
+func replay(ctx context.Context, s storage.Storage, from, to, batchSize uint64) error {
+	for start := from; start <= to; start += batchSize {
+		end := start + batchSize - 1
+		if end > to {
+			end = to
+		}
+
+		msgs, err := s.ListMessages(ctx, start, end)
+		if err != nil {
+			return fmt.Errorf("failed to list messages: %w", err)
+		}
+
+		for _, m := range msgs {
+			if err := replayMessage(ctx, s, m); err != nil {
+				return fmt.Errorf("failed to replay message %d/%d/%d: %w", m.Height, m.TxIndex, m.MsgIndex, err)
+			}
+		}
+
+		log.WithField("height", end).Infof("%d messages of blocks %d-%d replayed, %d blocks left", len(msgs), start, end, to-end)
+	}
+
+	return nil
+}
+
+// refreshViews refreshes views at once, because a running sync marks them dirty only on new blocks
+// and refreshes related posts rarely.
+func refreshViews(ctx context.Context, s storage.Storage) error {
+	if err := s.RefreshViews(ctx); err != nil {
+		return fmt.Errorf("failed to refresh views: %w", err)
+	}
+
+	if err := s.RefreshRelatedPosts(ctx); err != nil {
+		return fmt.Errorf("failed to refresh related posts: %w", err)
+	}
+
+	return nil
+}
+
+func replayMessage(ctx context.Context, s storage.Storage, m *storage.Message) error {
+	msg, err := decodeMessage(m)
+	if err != nil {
+		return err
+	}
+
+	switch msg := msg.(type) {
+	case *communitytypes.GenesisState:
+		return importCommunityGenesis(ctx, s, m.Time, msg)
+	case *tokentypes.GenesisState:
+		return importTokenGenesis(ctx, s, m.Time, msg)
+	case sdk.Msg:
+		return processMsg(ctx, s, m.Height, m.Time, msg)
+	default:
+		return fmt.Errorf("unexpected message type %s", m.Type) //nolint:goerr113
+	}
+}
+
+func decodeMessage(m *storage.Message) (proto.Message, error) {
+	msg, err := registry.Resolve(m.Type)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve type: %w", err)
+	}
+
+	if err := cdc.UnmarshalJSON(m.Payload, msg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal: %w", err)
+	}
+
+	return msg, nil
+}
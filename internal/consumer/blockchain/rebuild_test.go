@@ -0,0 +1,202 @@
+package blockchain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	communitytypes "github.com/Decentr-net/decentr/x/community/types"
+	operationstypes "github.com/Decentr-net/decentr/x/operations/types"
+	tokentypes "github.com/Decentr-net/decentr/x/token/types"
+
+	"github.com/Decentr-net/theseus/internal/storage"
+	storagemock "github.com/Decentr-net/theseus/internal/storage/mock"
+)
+
+const (
+	testOwner  = "decentr1u9slwz3sje8j94ccpwlslflg0506yc8y2ylmtz"
+	testOwner2 = "decentr1ltx6yymrs8eq4nmnhzfzxj6tspjuymh8mgd6gz"
+)
+
+func mustNewMessage(t *testing.T, height uint64, timestamp time.Time, msgIndex uint32, msg proto.Message) *storage.Message {
+	m, err := newMessage(height, timestamp, 0, msgIndex, msg)
+	require.NoError(t, err)
+	return m
+}
+
+func TestDecodeMessage(t *testing.T) {
+	tt := []proto.Message{
+		&communitytypes.MsgCreatePost{Post: communitytypes.Post{Uuid: "1234", Owner: testOwner, Title: "title"}},
+		&communitytypes.MsgSetLike{Like: communitytypes.Like{PostOwner: testOwner, PostUuid: "1234", Owner: testOwner2, Weight: -1}},
+		&operationstypes.MsgDistributeRewards{Owner: testOwner, Rewards: []operationstypes.Reward{
+			{Receiver: testOwner2, Reward: sdk.DecProto{Dec: sdk.NewDecWithPrec(100, 6)}},
+		}},
+		&tokentypes.GenesisState{Balances: map[string]sdk.DecProto{testOwner: {Dec: sdk.NewDec(2)}}},
+		&communitytypes.GenesisState{
+			Posts: []communitytypes.Post{},
+			Likes: []communitytypes.Like{},
+			Following: map[string]communitytypes.GenesisState_AddressList{
+				testOwner: {Address: []string{testOwner2}},
+			},
+		},
+	}
+
+	for i := range tt {
+		msg := tt[i]
+		t.Run(proto.MessageName(msg), func(t *testing.T) {
+			out, err := decodeMessage(mustNewMessage(t, 1, time.Now(), 0, msg))
+			require.NoError(t, err)
+			require.Equal(t, msg, out)
+		})
+	}
+}
+
+func TestRebuild(t *testing.T) {
+	genesisTime := time.Now().UTC()
+	blockTime := genesisTime.Add(time.Hour)
+
+	s := storagemock.NewMockStorage(gomock.NewController(t))
+
+	s.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(_ storage.Storage) error) error {
+		return f(s)
+	})
+
+	gomock.InOrder(
+		s.EXPECT().GetHeight(gomock.Any()).Return(uint64(3), nil),
+		s.EXPECT().ListJournalRanges(gomock.Any()).Return([]*storage.HeightRange{{From: 0, To: 3}}, nil),
+		s.EXPECT().TruncateDerived(gomock.Any()).Return(nil),
+		s.EXPECT().GetHeight(gomock.Any()).Return(uint64(3), nil),
+
+		s.EXPECT().ListMessages(gomock.Any(), uint64(0), uint64(1)).Return([]*storage.Message{
+			mustNewMessage(t, 0, genesisTime, 0, &tokentypes.GenesisState{
+				Balances: map[string]sdk.DecProto{testOwner: {Dec: sdk.NewDec(2)}},
+			}),
+			mustNewMessage(t, 0, genesisTime, 1, &communitytypes.GenesisState{}),
+			mustNewMessage(t, 1, blockTime, 0, &communitytypes.MsgFollow{Owner: testOwner, Whom: testOwner2}),
+		}, nil),
+		s.EXPECT().AddPDV(gomock.Any(), &storage.PDV{
+			Address:   testOwner,
+			Amount:    storage.PDVDenominator,
+			Source:    storage.GenesisPDVSource,
+			Timestamp: genesisTime,
+		}).Return(nil),
+		s.EXPECT().Follow(gomock.Any(), testOwner, testOwner2, uint64(1), blockTime).Return(nil),
+
+		s.EXPECT().ListMessages(gomock.Any(), uint64(2), uint64(3)).Return([]*storage.Message{
+			mustNewMessage(t, 3, blockTime, 0, &communitytypes.MsgCreatePost{Post: communitytypes.Post{Uuid: "1234", Owner: testOwner}}),
+		}, nil),
+		s.EXPECT().CreatePost(gomock.Any(), &storage.CreatePostParams{UUID: "1234", Owner: testOwner, CreatedAt: blockTime}).Return(nil),
+
+		s.EXPECT().RefreshViews(gomock.Any()).Return(nil),
+		s.EXPECT().RefreshRelatedPosts(gomock.Any()).Return(nil),
+	)
+
+	require.NoError(t, Rebuild(context.Background(), s, 2))
+}
+
+func TestRebuild_Errors(t *testing.T) {
+	require.ErrorIs(t, Rebuild(context.Background(), nil, 0), ErrInvalidBatchSize)
+
+	s := storagemock.NewMockStorage(gomock.NewController(t))
+
+	s.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(_ storage.Storage) error) error {
+		return f(s)
+	})
+	s.EXPECT().GetHeight(gomock.Any()).Return(uint64(1), nil).Times(2)
+	s.EXPECT().ListJournalRanges(gomock.Any()).Return([]*storage.HeightRange{{From: 0, To: 1}}, nil)
+	s.EXPECT().TruncateDerived(gomock.Any()).Return(nil)
+	s.EXPECT().ListMessages(gomock.Any(), uint64(0), uint64(1)).Return(nil, errTest)
+
+	require.ErrorIs(t, Rebuild(context.Background(), s, 10), errTest)
+}
+
+func TestRebuild_IncompleteJournal(t *testing.T) {
+	tt := []struct {
+		name   string
+		ranges []*storage.HeightRange
+		err    string
+	}{
+		{
+			name: "empty",
+			err:  "messages journal is incomplete: genesis is missing",
+		},
+		{
+			name:   "without_genesis",
+			ranges: []*storage.HeightRange{{From: 3, To: 10}},
+			err:    "messages journal is incomplete: genesis is missing",
+		},
+		{
+			name:   "gap",
+			ranges: []*storage.HeightRange{{From: 0, To: 3}, {From: 6, To: 10}},
+			err:    "messages journal is incomplete: blocks 4-5 are missing",
+		},
+		{
+			name:   "behind",
+			ranges: []*storage.HeightRange{{From: 0, To: 7}},
+			err:    "messages journal is incomplete: blocks 8-10 are missing",
+		},
+	}
+
+	for i := range tt {
+		tc := tt[i]
+		t.Run(tc.name, func(t *testing.T) {
+			s := storagemock.NewMockStorage(gomock.NewController(t))
+
+			s.EXPECT().InTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f func(_ storage.Storage) error) error {
+				return f(s)
+			})
+			s.EXPECT().GetHeight(gomock.Any()).Return(uint64(10), nil)
+			s.EXPECT().ListJournalRanges(gomock.Any()).Return(tc.ranges, nil)
+
+			// derived tables are not truncated
+			err := Rebuild(context.Background(), s, 10)
+			require.ErrorIs(t, err, ErrIncompleteJournal)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestImportGenesis(t *testing.T) {
+	timestamp := time.Now().UTC()
+
+	token := &tokentypes.GenesisState{
+		Balances: map[string]sdk.DecProto{testOwner: {Dec: sdk.NewDec(3)}},
+	}
+	community := &communitytypes.GenesisState{
+		Posts: []communitytypes.Post{{Uuid: "1234", Owner: testOwner, Title: "title"}},
+		Likes: []communitytypes.Like{{PostOwner: testOwner, PostUuid: "1234", Owner: testOwner2, Weight: 1}},
+		Following: map[string]communitytypes.GenesisState_AddressList{
+			testOwner2: {Address: []string{testOwner}},
+		},
+	}
+
+	s := storagemock.NewMockStorage(gomock.NewController(t))
+
+	gomock.InOrder(
+		s.EXPECT().AddMessage(gomock.Any(), mustNewMessage(t, 0, timestamp, 0, token)).Return(nil),
+		s.EXPECT().AddMessage(gomock.Any(), mustNewMessage(t, 0, timestamp, 1, community)).Return(nil),
+		s.EXPECT().AddJournalRange(gomock.Any(), uint64(0), uint64(0)).Return(nil),
+		s.EXPECT().AddPDV(gomock.Any(), &storage.PDV{
+			Address:   testOwner,
+			Amount:    2 * storage.PDVDenominator,
+			Source:    storage.GenesisPDVSource,
+			Timestamp: timestamp,
+		}).Return(nil),
+		s.EXPECT().Follow(gomock.Any(), testOwner2, testOwner, uint64(0), timestamp).Return(nil),
+		s.EXPECT().CreatePost(gomock.Any(), &storage.CreatePostParams{
+			UUID:      "1234",
+			Owner:     testOwner,
+			Title:     "title",
+			CreatedAt: timestamp,
+		}).Return(nil),
+		s.EXPECT().SetLike(gomock.Any(), storage.PostID{Owner: testOwner, UUID: "1234"},
+			communitytypes.LikeWeight_LIKE_WEIGHT_UP, timestamp, testOwner2).Return(nil),
+	)
+
+	require.NoError(t, ImportGenesis(context.Background(), s, timestamp, token, community))
+}
@@ -0,0 +1,89 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: refresher.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockRefresher is a mock of Refresher interface
+type MockRefresher struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefresherMockRecorder
+}
+
+// MockRefresherMockRecorder is the mock recorder for MockRefresher
+type MockRefresherMockRecorder struct {
+	mock *MockRefresher
+}
+
+// NewMockRefresher creates a new mock instance
+func NewMockRefresher(ctrl *gomock.Controller) *MockRefresher {
+	mock := &MockRefresher{ctrl: ctrl}
+	mock.recorder = &MockRefresherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRefresher) EXPECT() *MockRefresherMockRecorder {
+	return m.recorder
+}
+
+// Ping mocks base method
+func (m *MockRefresher) Ping(ctx context.Context) (interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Ping indicates an expected call of Ping
+func (mr *MockRefresherMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockRefresher)(nil).Ping), ctx)
+}
+
+// Name mocks base method
+func (m *MockRefresher) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name
+func (mr *MockRefresherMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockRefresher)(nil).Name))
+}
+
+// MarkDirty mocks base method
+func (m *MockRefresher) MarkDirty() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "MarkDirty")
+}
+
+// MarkDirty indicates an expected call of MarkDirty
+func (mr *MockRefresherMockRecorder) MarkDirty() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDirty", reflect.TypeOf((*MockRefresher)(nil).MarkDirty))
+}
+
+// Run mocks base method
+func (m *MockRefresher) Run(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Run indicates an expected call of Run
+func (mr *MockRefresherMockRecorder) Run(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockRefresher)(nil).Run), ctx)
+}
@@ -0,0 +1,137 @@
+// Package refresher contains background refresher of materialized views.
+package refresher
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Decentr-net/go-api/health"
+)
+
+//go:generate mockgen -destination=./mock/refresher.go -package=mock -source=refresher.go
+
+// nolint:gochecknoglobals
+var log = logrus.WithField("package", "refresher")
+
+// Refresher refreshes materialized views in background.
+type Refresher interface {
+	health.Pinger
+
+	// MarkDirty notifies that views are outdated. It never blocks.
+	MarkDirty()
+	// Run refreshes dirty or expired views every interval until ctx is done.
+	Run(ctx context.Context) error
+}
+
+// Status is a meta information returned by Ping.
+type Status struct {
+	LastRefresh time.Time `json:"last_refresh"`
+	Lag         string    `json:"lag"`
+}
+
+type refresher struct {
+	name     string
+	f        func(ctx context.Context) error
+	interval time.Duration
+	maxAge   time.Duration
+
+	mu          sync.Mutex
+	dirtySince  time.Time // zero when views are fresh
+	lastRefresh time.Time
+}
+
+// New returns new Refresher instance, f refreshes views and name is used for health check and logs.
+// Dirty signals received between refreshes are coalesced into one refresh.
+// Views are dirty on start and are refreshed at least every maxAge, because some of them depend on the current date.
+func New(name string, f func(ctx context.Context) error, interval, maxAge time.Duration) Refresher {
+	return &refresher{
+		name:       name,
+		f:          f,
+		interval:   interval,
+		maxAge:     maxAge,
+		dirtySince: time.Now(),
+	}
+}
+
+func (r *refresher) Name() string {
+	return r.name
+}
+
+func (r *refresher) Ping(_ context.Context) (interface{}, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var lag time.Duration
+	if !r.dirtySince.IsZero() {
+		lag = time.Since(r.dirtySince)
+	}
+
+	return Status{
+		LastRefresh: r.lastRefresh,
+		Lag:         lag.String(),
+	}, nil
+}
+
+func (r *refresher) MarkDirty() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.dirtySince.IsZero() {
+		r.dirtySince = time.Now()
+	}
+}
+
+func (r *refresher) Run(ctx context.Context) error {
+	t := time.NewTicker(r.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			r.refresh(ctx)
+		}
+	}
+}
+
+func (r *refresher) refresh(ctx context.Context) {
+	r.mu.Lock()
+	since := r.dirtySince
+	expired := time.Since(r.lastRefresh) >= r.maxAge
+	r.dirtySince = time.Time{}
+	r.mu.Unlock()
+
+	if since.IsZero() && !expired {
+		return
+	}
+
+	log := log.WithField("views", r.name)
+
+	if err := r.f(ctx); err != nil {
+		if ctx.Err() == nil {
+			log.WithError(err).Error("failed to refresh views")
+		}
+
+		// signals received during the refresh are covered by the earlier dirty time
+		r.mu.Lock()
+		r.dirtySince = since
+		r.mu.Unlock()
+
+		return
+	}
+
+	r.mu.Lock()
+	r.lastRefresh = time.Now()
+	r.mu.Unlock()
+
+	if since.IsZero() {
+		log.Debug("expired views refreshed")
+		return
+	}
+
+	log.WithField("lag", time.Since(since)).Debug("views refreshed")
+}
@@ -0,0 +1,84 @@
+package refresher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	storagemock "github.com/Decentr-net/theseus/internal/storage/mock"
+)
+
+var errTest = errors.New("test")
+
+func TestRefresher_Run(t *testing.T) {
+	s := storagemock.NewMockStorage(gomock.NewController(t))
+
+	r := New("views", s.RefreshViews, time.Millisecond, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	gomock.InOrder(
+		s.EXPECT().RefreshViews(gomock.Any()).Return(errTest),
+		s.EXPECT().RefreshViews(gomock.Any()).DoAndReturn(func(context.Context) error {
+			cancel()
+			return nil
+		}),
+	)
+
+	// signals are coalesced with the initial one into one refresh, failed refresh is retried
+	r.MarkDirty()
+	r.MarkDirty()
+	r.MarkDirty()
+
+	require.ErrorIs(t, r.Run(ctx), context.Canceled)
+
+	m, err := r.Ping(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "0s", m.(Status).Lag)
+	require.False(t, m.(Status).LastRefresh.IsZero())
+}
+
+func TestRefresher_Run_NotDirty(t *testing.T) {
+	s := storagemock.NewMockStorage(gomock.NewController(t))
+
+	r := New("views", s.RefreshViews, time.Millisecond, time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// views are refreshed on start only
+	s.EXPECT().RefreshViews(gomock.Any()).Return(nil)
+
+	require.ErrorIs(t, r.Run(ctx), context.DeadlineExceeded)
+}
+
+func TestRefresher_Run_Expired(t *testing.T) {
+	s := storagemock.NewMockStorage(gomock.NewController(t))
+
+	r := New("views", s.RefreshViews, time.Millisecond, 2*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// views are refreshed without dirty signals once they are expired
+	s.EXPECT().RefreshViews(gomock.Any()).Return(nil).MinTimes(3)
+
+	require.ErrorIs(t, r.Run(ctx), context.DeadlineExceeded)
+}
+
+func TestRefresher_Ping(t *testing.T) {
+	r := New("views", nil, time.Hour, time.Hour)
+
+	// views are dirty until the first refresh
+	time.Sleep(time.Millisecond)
+
+	m, err := r.Ping(context.Background())
+	require.NoError(t, err)
+	require.NotEqual(t, "0s", m.(Status).Lag)
+	require.True(t, m.(Status).LastRefresh.IsZero())
+}
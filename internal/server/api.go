@@ -6,6 +6,7 @@ import (
 
 const maxLimit = 100
 const defaultLimit = 20
+const defaultDenom = "udec"
 
 // ListPostsResponse ...
 // swagger:model
@@ -15,6 +16,23 @@ type ListPostsResponse struct {
 	ProfileStats map[string]ProfileStats `json:"profileStats"`
 	// Posts' statistics dictionary where key is a full form ID (owner/uuid) and value is statistics
 	Stats map[string][]StatsItem `json:"stats"`
+	// NextCursor is passed as cursor to get the next page, it's empty for the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// SearchPostsResponse ...
+// swagger:model
+type SearchPostsResponse struct {
+	Posts []*Post `json:"posts"`
+	// ProfileStats contains profiles stats.
+	ProfileStats map[string]ProfileStats `json:"profileStats"`
+	// Posts' statistics dictionary where key is a full form ID (owner/uuid) and value is statistics
+	Stats map[string][]StatsItem `json:"stats"`
+	// Snippets dictionary where key is a full form ID (owner/uuid) and value is a fragment of post text
+	// with matched words wrapped in <b> tag.
+	Snippets map[string]string `json:"snippets"`
+	// NextCursor is passed as cursor to get the next page, it's empty for the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 
 // GetPostResponse ...
@@ -41,6 +59,22 @@ type Post struct {
 	CreatedAt     uint64                `json:"createdAt"`
 }
 
+// ListLikesResponse ...
+// swagger:model
+type ListLikesResponse struct {
+	Likes []*Like `json:"likes"`
+	// NextCursor is passed as cursor to get the next page, it's empty for the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// Like ...
+type Like struct {
+	LikedBy string               `json:"likedBy"`
+	Weight  community.LikeWeight `json:"weight"`
+	// LikedAt is a like block time in unix seconds.
+	LikedAt uint64 `json:"likedAt"`
+}
+
 // SharePost ...
 // swagger:model
 type SharePost struct {
@@ -52,8 +86,94 @@ type SharePost struct {
 // ProfileStats ...
 // swagger:model
 type ProfileStats struct {
-	PostsCount uint16      `json:"postsCount"`
-	Stats      []StatsItem `json:"stats"`
+	PostsCount     uint16 `json:"postsCount"`
+	FollowersCount uint32 `json:"followersCount"`
+	FollowingCount uint32 `json:"followingCount"`
+	// LikesCount and DislikesCount are received by profile's posts.
+	LikesCount    uint32      `json:"likesCount"`
+	DislikesCount uint32      `json:"dislikesCount"`
+	Stats         []StatsItem `json:"stats"`
+}
+
+// FollowersStats ...
+// swagger:model
+type FollowersStats struct {
+	// Followers is a current followers count.
+	Followers int64 `json:"followers"`
+	// Stats contains followers count at the end of every day when it was changed.
+	Stats []StatsItem `json:"stats"`
+}
+
+// ListFollowsResponse ...
+// swagger:model
+type ListFollowsResponse struct {
+	// Total is a total count of followers or followings.
+	Total   uint32    `json:"total"`
+	Follows []*Follow `json:"follows"`
+	// NextCursor is passed as cursor to get the next page, it's empty for the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// Follow ...
+type Follow struct {
+	Address string `json:"address"`
+	// FollowedAt is a follow block time in unix seconds, it's 0 for follows made before the time was tracked.
+	FollowedAt uint64 `json:"followedAt"`
+	// Followed is set when requestedBy is passed and tells if the requester follows the address.
+	Followed *bool `json:"followed,omitempty"`
+}
+
+// ListFollowSuggestionsResponse ...
+// swagger:model
+type ListFollowSuggestionsResponse struct {
+	Suggestions []*FollowSuggestion `json:"suggestions"`
+}
+
+// FollowSuggestion ...
+type FollowSuggestion struct {
+	Address string `json:"address"`
+	// FollowedByFollowees is a count of the profile's followees who follow the address.
+	FollowedByFollowees uint32 `json:"followedByFollowees"`
+	// LikedPosts is a count of the address' posts liked by the profile.
+	LikedPosts uint32 `json:"likedPosts"`
+}
+
+// ListCategoriesResponse ...
+// swagger:model
+type ListCategoriesResponse struct {
+	Categories []*CategoryStats `json:"categories"`
+}
+
+// CategoryStats ...
+type CategoryStats struct {
+	ID         community.Category `json:"id"`
+	Name       string             `json:"name"`
+	PostsCount uint32             `json:"postsCount"`
+	// ActiveAuthors is a count of authors posted in the category during the last 30 days.
+	ActiveAuthors uint32 `json:"activeAuthors"`
+	// LikesCount is received by category's posts.
+	LikesCount uint32 `json:"likesCount"`
+	// Stats contains count of posts created by date during the last 90 days.
+	Stats []StatsItem `json:"stats"`
+}
+
+// LeaderboardResponse ...
+// swagger:model
+type LeaderboardResponse struct {
+	Items []*LeaderboardItem `json:"items"`
+	// AddressItem is a rank of the requested address, it's set when address is passed and has a rank.
+	AddressItem *LeaderboardItem `json:"addressItem,omitempty"`
+	// NextCursor is passed as cursor to get the next page, it's empty for the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// LeaderboardItem ...
+type LeaderboardItem struct {
+	Address string `json:"address"`
+	// Value is earned pdv, received likes or created posts count.
+	Value float64 `json:"value"`
+	// Rank starts from 1, addresses with equal values have equal ranks.
+	Rank uint64 `json:"rank"`
 }
 
 // DDVStats ...
@@ -76,3 +196,81 @@ type StatsItem struct {
 	Date  string  `json:"date"`
 	Value float64 `json:"value"`
 }
+
+// ListTokenMovementsResponse ...
+// swagger:model
+type ListTokenMovementsResponse struct {
+	Movements []*TokenMovement `json:"movements"`
+	// NextCursor is passed as cursor to get the next page, it's empty for the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// TokenMovement is a mint or a burn of tokens.
+// swagger:model
+type TokenMovement struct {
+	ID uint64 `json:"id"`
+	// Type is mint or burn.
+	Type    string `json:"type"`
+	Address string `json:"address"`
+	Denom   string `json:"denom"`
+	Amount  int64  `json:"amount"`
+	// Timestamp is a block time in unix seconds.
+	Timestamp uint64 `json:"timestamp"`
+}
+
+// SupplyStats ...
+// swagger:model
+type SupplyStats struct {
+	Denom  string `json:"denom"`
+	Minted int64  `json:"minted"`
+	Burned int64  `json:"burned"`
+	// Net is a whole supply change caused by mints and burns.
+	Net   int64             `json:"net"`
+	Stats []SupplyStatsItem `json:"stats"`
+}
+
+// SupplyStatsItem ...
+// Date is RFC3999 date, supplyChange is a net supply change since the first movement up to the date.
+type SupplyStatsItem struct {
+	Date         string `json:"date"`
+	Minted       int64  `json:"minted"`
+	Burned       int64  `json:"burned"`
+	Net          int64  `json:"net"`
+	SupplyChange int64  `json:"supplyChange"`
+}
+
+// Balances ...
+// swagger:model
+type Balances struct {
+	Balances []Balance `json:"balances"`
+}
+
+// Balance ...
+// PDV balance has pdv denom and is denominated, it's returned for every address.
+type Balance struct {
+	Denom   string      `json:"denom"`
+	Amount  float64     `json:"amount"`
+	History []StatsItem `json:"history"`
+}
+
+// ListPDVResponse ...
+// swagger:model
+type ListPDVResponse struct {
+	PDV []*PDV `json:"pdv"`
+	// NextCursor is passed as cursor to get the next page, it's empty for the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// PDV is a change of profile pdv.
+type PDV struct {
+	ID     uint64  `json:"id"`
+	Amount float64 `json:"amount"`
+	// Source is genesis, reward, like or unknown for changes saved before sources were tracked.
+	Source string `json:"source"`
+	// Post is a full form ID (owner/uuid) of liked post.
+	Post string `json:"post,omitempty"`
+	// Counterparty is a liker or a rewards distributor.
+	Counterparty string `json:"counterparty,omitempty"`
+	// Timestamp is a block time in unix seconds.
+	Timestamp uint64 `json:"timestamp"`
+}
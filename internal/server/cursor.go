@@ -0,0 +1,236 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Decentr-net/theseus/internal/storage"
+)
+
+var errInvalidCursor = fmt.Errorf("%w: invalid cursor", errInvalidRequest)
+
+// postsCursor is a position in posts list.
+// It keeps sort params to reject the cursor used with another sorting.
+type postsCursor struct {
+	SortBy  storage.SortType  `json:"s"`
+	OrderBy storage.OrderType `json:"o"`
+	Value   int64             `json:"v"`
+	Score   float64           `json:"c,omitempty"`
+	Owner   string            `json:"w"`
+	UUID    string            `json:"u"`
+	// Now is a reference time of rising score in unix microseconds.
+	Now int64 `json:"n,omitempty"`
+}
+
+// newPostsCursor returns cursor pointing to the post in list sorted by sortBy.
+func newPostsCursor(p *storage.Post, sortBy storage.SortType, orderBy storage.OrderType) postsCursor {
+	c := postsCursor{
+		SortBy:  sortBy,
+		OrderBy: orderBy,
+		Owner:   p.Owner,
+		UUID:    p.UUID,
+	}
+
+	switch sortBy {
+	case storage.CreatedAtSortType:
+		c.Value = p.CreatedAt.UnixMicro()
+	case storage.LikesSortType:
+		c.Value = int64(p.Likes)
+	case storage.DislikesSortType:
+		c.Value = int64(p.Dislikes)
+	case storage.PDVSortType:
+		c.Value = p.UPDV
+	case storage.HotSortType, storage.RisingSortType, storage.BestSortType, storage.ControversialSortType:
+		c.Score = p.Score
+	}
+
+	return c
+}
+
+// toStorage returns nil for the cursor without position, it points to the beginning of the list.
+func (c postsCursor) toStorage() *storage.PostsCursor {
+	if c.Owner == "" {
+		return nil
+	}
+
+	return &storage.PostsCursor{
+		Value: c.Value,
+		Score: c.Score,
+		Owner: c.Owner,
+		UUID:  c.UUID,
+	}
+}
+
+// searchCursor is a position in search results.
+type searchCursor struct {
+	Rank  float64 `json:"r"`
+	Owner string  `json:"w"`
+	UUID  string  `json:"u"`
+}
+
+func newSearchCursor(r *storage.SearchResult) searchCursor {
+	return searchCursor{
+		Rank:  r.Rank,
+		Owner: r.Post.Owner,
+		UUID:  r.Post.UUID,
+	}
+}
+
+func (c searchCursor) toStorage() *storage.SearchCursor {
+	return &storage.SearchCursor{
+		Rank:  c.Rank,
+		Owner: c.Owner,
+		UUID:  c.UUID,
+	}
+}
+
+// followsCursor is a position in followers or followings list.
+type followsCursor struct {
+	CreatedAt int64  `json:"t"`
+	Address   string `json:"a"`
+}
+
+func newFollowsCursor(f *storage.Follow, listed string) followsCursor {
+	return followsCursor{
+		CreatedAt: f.CreatedAt.UnixMicro(),
+		Address:   listed,
+	}
+}
+
+func (c followsCursor) toStorage() *storage.FollowsCursor {
+	return &storage.FollowsCursor{
+		CreatedAt: time.UnixMicro(c.CreatedAt).UTC(),
+		Address:   c.Address,
+	}
+}
+
+// likesCursor is a position in post likes list.
+type likesCursor struct {
+	LikedAt int64  `json:"t"`
+	LikedBy string `json:"a"`
+}
+
+func newLikesCursor(l *storage.Like) likesCursor {
+	return likesCursor{
+		LikedAt: l.LikedAt.UnixMicro(),
+		LikedBy: l.LikedBy,
+	}
+}
+
+func (c likesCursor) toStorage() *storage.LikesCursor {
+	return &storage.LikesCursor{
+		LikedAt: time.UnixMicro(c.LikedAt).UTC(),
+		LikedBy: c.LikedBy,
+	}
+}
+
+// pdvCursor is a position in pdv changes list.
+type pdvCursor struct {
+	ID uint64 `json:"i"`
+}
+
+func newPDVCursor(p *storage.PDV) pdvCursor {
+	return pdvCursor{
+		ID: p.ID,
+	}
+}
+
+func (c pdvCursor) toStorage() *uint64 {
+	return &c.ID
+}
+
+// tokenMovementsCursor is a position in token movements list.
+type tokenMovementsCursor struct {
+	ID uint64 `json:"i"`
+}
+
+func newTokenMovementsCursor(m *storage.TokenMovement) tokenMovementsCursor {
+	return tokenMovementsCursor{
+		ID: m.ID,
+	}
+}
+
+func (c tokenMovementsCursor) toStorage() *uint64 {
+	return &c.ID
+}
+
+// leaderboardCursor is a position in leaderboard.
+// It keeps metric and period to reject the cursor used with another leaderboard.
+type leaderboardCursor struct {
+	Metric  storage.LeaderboardMetric `json:"m"`
+	Period  storage.LeaderboardPeriod `json:"p"`
+	Value   int64                     `json:"v"`
+	Address string                    `json:"a"`
+}
+
+func newLeaderboardCursor(i *storage.LeaderboardItem, metric storage.LeaderboardMetric, period storage.LeaderboardPeriod) leaderboardCursor {
+	return leaderboardCursor{
+		Metric:  metric,
+		Period:  period,
+		Value:   i.Value,
+		Address: i.Address,
+	}
+}
+
+func (c leaderboardCursor) toStorage() *storage.LeaderboardCursor {
+	return &storage.LeaderboardCursor{
+		Value:   c.Value,
+		Address: c.Address,
+	}
+}
+
+// cursorCodec encodes cursors into opaque strings signed with HMAC-SHA256.
+// Signature guarantees that cursor was issued by the service, so its content can be trusted.
+type cursorCodec struct {
+	secret []byte
+}
+
+func (c cursorCodec) encode(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cursor: %w", err)
+	}
+
+	return fmt.Sprintf("%s.%s",
+		base64.RawURLEncoding.EncodeToString(b),
+		base64.RawURLEncoding.EncodeToString(c.sign(b)),
+	), nil
+}
+
+func (c cursorCodec) decode(s string, v interface{}) error {
+	parts := strings.Split(s, ".")
+	if len(parts) != 2 {
+		return errInvalidCursor
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return errInvalidCursor
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return errInvalidCursor
+	}
+
+	if !hmac.Equal(sig, c.sign(b)) {
+		return errInvalidCursor
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		return errInvalidCursor
+	}
+
+	return nil
+}
+
+func (c cursorCodec) sign(b []byte) []byte {
+	h := hmac.New(sha256.New, c.secret)
+	h.Write(b) // nolint: errcheck
+	return h.Sum(nil)
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/Decentr-net/theseus/internal/storage"
+)
+
+func Test_cursorCodec(t *testing.T) {
+	c := cursorCodec{secret: []byte("secret")}
+
+	in := postsCursor{
+		SortBy:  storage.PDVSortType,
+		OrderBy: storage.AscendingOrder,
+		Value:   -10,
+		Owner:   "owner",
+		UUID:    "uuid",
+	}
+
+	s, err := c.encode(in)
+	require.NoError(t, err)
+
+	var out postsCursor
+	require.NoError(t, c.decode(s, &out))
+	require.Equal(t, in, out)
+
+	// cursor signed with another secret
+	require.ErrorIs(t, cursorCodec{secret: []byte("another")}.decode(s, &out), errInvalidCursor)
+
+	for _, v := range []string{"", "invalid", "a.b.c", s + "a", "e30." + s[len(s)-43:]} {
+		require.ErrorIs(t, c.decode(v, &out), errInvalidCursor, v)
+	}
+}
+
+func Test_newPostsCursor(t *testing.T) {
+	p := storage.Post{
+		Owner:     "owner",
+		UUID:      "uuid",
+		CreatedAt: time.Unix(1, 1000),
+		Likes:     2,
+		Dislikes:  3,
+		UPDV:      -4,
+		Score:     1.5,
+	}
+
+	for sortBy, v := range map[storage.SortType]int64{
+		storage.CreatedAtSortType: 1000001,
+		storage.LikesSortType:     2,
+		storage.DislikesSortType:  3,
+		storage.PDVSortType:       -4,
+	} {
+		require.Equal(t, postsCursor{
+			SortBy:  sortBy,
+			OrderBy: storage.DescendingOrder,
+			Value:   v,
+			Owner:   "owner",
+			UUID:    "uuid",
+		}, newPostsCursor(&p, sortBy, storage.DescendingOrder))
+	}
+
+	for _, sortBy := range []storage.SortType{
+		storage.HotSortType, storage.RisingSortType, storage.BestSortType, storage.ControversialSortType,
+	} {
+		require.Equal(t, postsCursor{
+			SortBy:  sortBy,
+			OrderBy: storage.AscendingOrder,
+			Score:   1.5,
+			Owner:   "owner",
+			UUID:    "uuid",
+		}, newPostsCursor(&p, sortBy, storage.AscendingOrder))
+	}
+}
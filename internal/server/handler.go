@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -8,6 +9,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi"
 
@@ -42,12 +44,15 @@ func (s server) listPosts(w http.ResponseWriter, r *http.Request) {
 	//   maximum: 9
 	//   example: 4
 	// - name: sortBy
-	//   description: sets posts' field to be sorted by
+	//   description: sets posts' field to be sorted by. hot is net likes decayed by age,
+	//     rising is net likes received during the last day decayed by age, it lists only posts with positive ones,
+	//     best is lower bound of Wilson score interval for a share of likes, controversial is votes count
+	//     weighted by balance of likes and dislikes
 	//   in: query
 	//   required: false
 	//   default: createdAt
 	//   type: string
-	//   enum: [created_at, likesCount, dislikesCount, pdv]
+	//   enum: [created_at, likesCount, dislikesCount, pdv, hot, rising, best, controversial]
 	//   example: likesCount
 	// - name: orderBy
 	//   description: sets sort's direct
@@ -79,8 +84,12 @@ func (s server) listPosts(w http.ResponseWriter, r *http.Request) {
 	//   default: 20
 	//   minimum: 1
 	//   maximum: 100
+	// - name: cursor
+	//   description: sets position after which posts will be returned, it's nextCursor of previous page
+	//   in: query
+	//   required: false
 	// - name: after
-	//   description: sets not-including bound for list by post id(`owner/uuid`)
+	//   description: deprecated, use cursor; sets not-including bound by post id(`owner/uuid`), not for score sorts
 	//   in: query
 	//   required: false
 	//   example: decentr1ltx6yymrs8eq4nmnhzfzxj6tspjuymh8mgd6gz/df870e39-6fcb-11eb-9461-0242ac11000b
@@ -99,6 +108,11 @@ func (s server) listPosts(w http.ResponseWriter, r *http.Request) {
 	//   description: adds liked flag to response
 	//   required: false
 	//   example: decentr1ltx6yymrs8eq4nmnhzfzxj6tspjuymh8mgd6gz
+	// - name: q
+	//   description: filters posts by full-text search query, quoted phrases, OR and -word are supported
+	//   in: query
+	//   required: false
+	//   example: decentr -bitcoin
 	// - name: excludeNegative
 	//   in: query
 	//   description: excludes posts with negative pdv
@@ -121,77 +135,273 @@ func (s server) listPosts(w http.ResponseWriter, r *http.Request) {
 	//     schema:
 	//       "$ref": "#/definitions/Error"
 
-	params, err := extractListParamsFromQuery(r.URL.Query())
+	q := r.URL.Query()
+
+	params, err := extractListParamsFromQuery(q)
 	if err != nil {
 		api.WriteError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	posts, err := s.s.ListPosts(r.Context(), params)
+	if c := q.Get("cursor"); c != "" {
+		var pc postsCursor
+		if err := s.cursor.decode(c, &pc); err != nil {
+			api.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if pc.SortBy != params.SortBy || pc.OrderBy != params.OrderBy {
+			api.WriteError(w, http.StatusBadRequest, errInvalidCursor.Error())
+			return
+		}
+
+		params.After = pc.toStorage()
+		if pc.Now != 0 {
+			params.Now = time.UnixMicro(pc.Now).UTC()
+		}
+	} else if after := q.Get("after"); after != "" {
+		// scores are not returned by GetPost, so the position can't be restored from the post
+		switch params.SortBy {
+		case storage.HotSortType, storage.RisingSortType, storage.BestSortType, storage.ControversialSortType:
+			api.WriteError(w, http.StatusBadRequest,
+				fmt.Sprintf("%s: after is not supported by %s sorting, use cursor", errInvalidRequest, params.SortBy))
+			return
+		}
+
+		id, err := parsePostID(after)
+		if err != nil {
+			api.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// cursor is restored from the post to support clients which don't use cursors yet
+		post, err := s.s.GetPost(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				api.WriteError(w, http.StatusBadRequest, "post from after is not found")
+				return
+			}
+			api.WriteInternalErrorf(r.Context(), w, "failed to get post: %s", err.Error())
+			return
+		}
+
+		params.After = newPostsCursor(post, params.SortBy, params.OrderBy).toStorage()
+	}
+
+	// rising scores depend on the time, so it's fixed by the first page and kept in cursors
+	if params.SortBy == storage.RisingSortType && params.Now.IsZero() {
+		params.Now = time.Now().UTC()
+	}
+
+	// first page, top post is not pinned to search results
+	var topPost *storage.Post
+	if q.Get("cursor") == "" && params.After == nil && params.Query == nil {
+		if topPost, err = s.s.GetPost(r.Context(), topPostID); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			api.WriteInternalErrorf(r.Context(), w, "failed to get top post: %s", err.Error())
+			return
+		}
+	}
+
+	// top post takes place of the last listed post
+	if topPost != nil {
+		params.Limit--
+	}
+
+	var posts []*storage.Post
+	if params.Limit > 0 {
+		if posts, err = s.s.ListPosts(r.Context(), params); err != nil {
+			api.WriteInternalErrorf(r.Context(), w, "failed to list posts: %s", err.Error())
+			return
+		}
+	}
+
+	page := posts
+	if topPost != nil {
+		page = append([]*storage.Post{topPost}, posts...)
+	}
+
+	resp, err := s.getListPostsResponse(r.Context(), page, q.Get("requestedBy"))
 	if err != nil {
-		api.WriteInternalErrorf(r.Context(), w, "failed to list posts: %s", err.Error())
+		api.WriteInternalErrorf(r.Context(), w, "failed to get posts meta: %s", err.Error())
 		return
 	}
 
-	// first page
-	if params.After == nil {
-		topPost, err := s.s.GetPost(r.Context(), topPostID)
-		if err != nil && !errors.Is(err, storage.ErrNotFound) {
-			api.WriteInternalErrorf(r.Context(), w, "failed to get top post: %s", err.Error())
+	// the page is full, so there could be more posts. The cursor points to the last listed post, not to the top one;
+	// it has no position when only the top post is on the page, so the next page starts from the beginning.
+	if len(posts) == int(params.Limit) && len(page) > 0 {
+		c := postsCursor{SortBy: params.SortBy, OrderBy: params.OrderBy}
+		if len(posts) > 0 {
+			c = newPostsCursor(posts[len(posts)-1], params.SortBy, params.OrderBy)
+		}
+
+		if params.SortBy == storage.RisingSortType {
+			c.Now = params.Now.UnixMicro()
+		}
+
+		resp.NextCursor, err = s.cursor.encode(c)
+		if err != nil {
+			api.WriteInternalErrorf(r.Context(), w, "failed to encode cursor: %s", err.Error())
 			return
 		}
+	}
+
+	api.WriteOK(w, http.StatusOK, resp)
+}
+
+func (s server) searchPosts(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /posts/search Community SearchPosts
+	//
+	// Returns posts matching the query ordered by relevance with additional meta information.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: q
+	//   description: full-text search query, quoted phrases, OR and -word are supported
+	//   in: query
+	//   required: true
+	//   example: decentr -bitcoin
+	// - name: category
+	//   description: filters posts by category
+	//   in: query
+	//   required: false
+	//   minimum: 1
+	//   maximum: 9
+	//   example: 4
+	// - name: limit
+	//   description: limits count of returned posts
+	//   in: query
+	//   required: false
+	//   default: 20
+	//   minimum: 1
+	//   maximum: 100
+	// - name: cursor
+	//   description: sets position after which posts will be returned, it's nextCursor of previous page
+	//   in: query
+	//   required: false
+	// - name: requestedBy
+	//   in: query
+	//   description: adds liked flag to response
+	//   required: false
+	//   example: decentr1ltx6yymrs8eq4nmnhzfzxj6tspjuymh8mgd6gz
+	// responses:
+	//   '200':
+	//     description: Posts
+	//     schema:
+	//       "$ref": "#/definitions/SearchPostsResponse"
+	//   '400':
+	//     description: bad request
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '500':
+	//     description: internal server error
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+
+	q := r.URL.Query()
+
+	if strings.TrimSpace(q.Get("q")) == "" {
+		api.WriteError(w, http.StatusBadRequest, "invalid query")
+		return
+	}
+
+	// search accepts a subset of list params
+	lp, err := extractListParamsFromQuery(url.Values{
+		"category": q["category"],
+		"limit":    q["limit"],
+	})
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sp := storage.SearchPostsParams{
+		Query:    q.Get("q"),
+		Category: lp.Category,
+		Limit:    lp.Limit,
+	}
 
-		if topPost != nil {
-			// prepend post
-			posts = append([]*storage.Post{topPost}, posts[:len(posts)-1]...)
+	if c := q.Get("cursor"); c != "" {
+		var sc searchCursor
+		if err := s.cursor.decode(c, &sc); err != nil {
+			api.WriteError(w, http.StatusBadRequest, err.Error())
+			return
 		}
+
+		sp.After = sc.toStorage()
 	}
 
-	profileStats, err := s.s.GetProfileStats(r.Context(), extractProfileIDsFromPosts(posts)...)
+	res, err := s.s.SearchPosts(r.Context(), &sp)
 	if err != nil {
-		api.WriteInternalErrorf(r.Context(), w, "failed to get profiles: %s", err.Error())
+		api.WriteInternalErrorf(r.Context(), w, "failed to search posts: %s", err.Error())
 		return
 	}
 
-	ids := extractPostIDsFromPosts(posts)
-	stats, err := s.s.GetPostStats(r.Context(), ids...)
+	posts := make([]*storage.Post, len(res))
+	for i, v := range res {
+		posts[i] = v.Post
+	}
+
+	lr, err := s.getListPostsResponse(r.Context(), posts, q.Get("requestedBy"))
 	if err != nil {
-		api.WriteInternalErrorf(r.Context(), w, "failed get stats: %s", err.Error())
+		api.WriteInternalErrorf(r.Context(), w, "failed to get posts meta: %s", err.Error())
 		return
 	}
 
-	var liked map[storage.PostID]community.LikeWeight
-	if requestedBy := r.URL.Query().Get("requestedBy"); requestedBy != "" {
-		liked, err = s.s.GetLikes(r.Context(), requestedBy, ids...)
-		if err != nil {
-			api.WriteInternalErrorf(r.Context(), w, "failed to get likes: %s", err.Error())
+	resp := SearchPostsResponse{
+		Posts:        lr.Posts,
+		ProfileStats: lr.ProfileStats,
+		Stats:        lr.Stats,
+		Snippets:     make(map[string]string, len(res)),
+	}
+	for _, v := range res {
+		resp.Snippets[fmt.Sprintf("%s/%s", v.Post.Owner, v.Post.UUID)] = v.Snippet
+	}
+
+	// the page is full, so there could be more posts
+	if len(res) > 0 && len(res) == int(sp.Limit) {
+		if resp.NextCursor, err = s.cursor.encode(newSearchCursor(res[len(res)-1])); err != nil {
+			api.WriteInternalErrorf(r.Context(), w, "failed to encode cursor: %s", err.Error())
 			return
 		}
 	}
 
-	api.WriteOK(w, http.StatusOK, newListPostsResponse(posts, profileStats, stats, liked))
+	api.WriteOK(w, http.StatusOK, resp)
 }
 
-func (s server) getSharePostBySlug(w http.ResponseWriter, r *http.Request) {
-	// swagger:operation GET /posts/{slug} Community GetPostBySlug
+func (s server) listFeed(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /feed/{address} Community ListFeed
 	//
-	// Get post by slug.
+	// Returns personalized feed of the profile: posts of followees, posts liked by followees and top posts
+	// in categories the profile likes and posts the most. Posts are sorted by hot score, liked flags are set for the profile.
 	//
 	// ---
 	// produces:
 	// - application/json
 	// parameters:
-	// - name: slug
+	// - name: address
 	//   in: path
 	//   required: true
 	//   type: string
+	// - name: limit
+	//   description: limits count of returned posts
+	//   in: query
+	//   required: false
+	//   default: 20
+	//   minimum: 1
+	//   maximum: 100
+	// - name: cursor
+	//   description: sets position after which posts will be returned, it's nextCursor of previous page
+	//   in: query
+	//   required: false
 	// responses:
 	//   '200':
-	//     description: Post
+	//     description: Posts
 	//     schema:
-	//       "$ref": "#/definitions/SharePost"
-	//   '404':
-	//     description: post not found
+	//       "$ref": "#/definitions/ListPostsResponse"
+	//   '400':
+	//     description: bad request
 	//     schema:
 	//       "$ref": "#/definitions/Error"
 	//   '500':
@@ -199,29 +409,88 @@ func (s server) getSharePostBySlug(w http.ResponseWriter, r *http.Request) {
 	//     schema:
 	//       "$ref": "#/definitions/Error"
 
-	slug := chi.URLParam(r, "slug")
+	q := r.URL.Query()
 
-	post, err := s.s.GetPostBySlug(r.Context(), slug)
-	if err != nil {
-		if errors.Is(err, storage.ErrNotFound) {
-			api.WriteError(w, http.StatusNotFound, "post not found")
+	params := storage.ListFeedParams{
+		Address: chi.URLParam(r, "address"),
+	}
+
+	var err error
+	if params.Limit, err = extractLimitFromQuery(q); err != nil {
+		api.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if c := q.Get("cursor"); c != "" {
+		var pc postsCursor
+		if err := s.cursor.decode(c, &pc); err != nil {
+			api.WriteError(w, http.StatusBadRequest, err.Error())
 			return
 		}
-		api.WriteInternalErrorf(r.Context(), w, "failed to get post: %s", err.Error())
+
+		if pc.SortBy != storage.HotSortType || pc.OrderBy != storage.DescendingOrder {
+			api.WriteError(w, http.StatusBadRequest, errInvalidCursor.Error())
+			return
+		}
+
+		params.After = pc.toStorage()
+	}
+
+	posts, err := s.s.ListFeed(r.Context(), &params)
+	if err != nil {
+		api.WriteInternalErrorf(r.Context(), w, "failed to list feed: %s", err.Error())
 		return
 	}
 
-	api.WriteOK(w, http.StatusOK, SharePost{
-		UUID:  post.UUID,
-		Owner: post.Owner,
-		Title: post.Title,
-	})
+	resp, err := s.getListPostsResponse(r.Context(), posts, params.Address)
+	if err != nil {
+		api.WriteInternalErrorf(r.Context(), w, "failed to get posts meta: %s", err.Error())
+		return
+	}
+
+	if len(posts) > 0 && len(posts) == int(params.Limit) {
+		resp.NextCursor, err = s.cursor.encode(newPostsCursor(posts[len(posts)-1], storage.HotSortType, storage.DescendingOrder))
+		if err != nil {
+			api.WriteInternalErrorf(r.Context(), w, "failed to encode cursor: %s", err.Error())
+			return
+		}
+	}
+
+	api.WriteOK(w, http.StatusOK, resp)
 }
 
-func (s server) getPost(w http.ResponseWriter, r *http.Request) {
-	// swagger:operation GET /posts/{owner}/{uuid} Community GetPostByID
+// getListPostsResponse collects profiles stats, posts stats and requester's likes of posts.
+func (s server) getListPostsResponse(
+	ctx context.Context,
+	posts []*storage.Post,
+	requestedBy string,
+) (ListPostsResponse, error) {
+	profileStats, err := s.s.GetProfileStats(ctx, extractProfileIDsFromPosts(posts)...)
+	if err != nil {
+		return ListPostsResponse{}, fmt.Errorf("failed to get profiles: %w", err)
+	}
+
+	ids := extractPostIDsFromPosts(posts)
+	stats, err := s.s.GetPostStats(ctx, ids...)
+	if err != nil {
+		return ListPostsResponse{}, fmt.Errorf("failed to get stats: %w", err)
+	}
+
+	var liked map[storage.PostID]community.LikeWeight
+	if requestedBy != "" {
+		liked, err = s.s.GetLikes(ctx, requestedBy, ids...)
+		if err != nil {
+			return ListPostsResponse{}, fmt.Errorf("failed to get likes: %w", err)
+		}
+	}
+
+	return newListPostsResponse(posts, profileStats, stats, liked), nil
+}
+
+func (s server) listLikes(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /posts/{owner}/{uuid}/likes Community ListLikes
 	//
-	// Get post by owner and uuid.
+	// Returns likes and dislikes of the post, the latest first.
 	//
 	// ---
 	// produces:
@@ -235,105 +504,1091 @@ func (s server) getPost(w http.ResponseWriter, r *http.Request) {
 	//   in: path
 	//   required: true
 	//   type: string
-	// - name: requestedBy
+	// - name: weight
+	//   description: filters likes by weight
+	//   in: query
+	//   required: false
+	//   type: integer
+	//   enum: [-1, 1]
+	// - name: limit
+	//   description: limits count of returned likes
+	//   in: query
+	//   required: false
+	//   default: 20
+	//   minimum: 1
+	//   maximum: 100
+	// - name: cursor
+	//   description: sets position after which likes will be returned, it's nextCursor of previous page
 	//   in: query
-	//   description: adds liked flag to response
 	//   required: false
-	//   example: decentr1ltx6yymrs8eq4nmnhzfzxj6tspjuymh8mgd6gz
 	// responses:
 	//   '200':
-	//     description: Post
+	//     description: Likes
 	//     schema:
-	//       "$ref": "#/definitions/GetPostResponse"
+	//       "$ref": "#/definitions/ListLikesResponse"
 	//   '400':
 	//     description: bad request
 	//     schema:
 	//       "$ref": "#/definitions/Error"
-	//   '404':
-	//     description: post not found
-	//     schema:
-	//       "$ref": "#/definitions/Error"
 	//   '500':
 	//     description: internal server error
 	//     schema:
 	//       "$ref": "#/definitions/Error"
 
-	owner, uuid := chi.URLParam(r, "owner"), chi.URLParam(r, "uuid")
+	params := storage.ListLikesParams{
+		Post: storage.PostID{Owner: chi.URLParam(r, "owner"), UUID: chi.URLParam(r, "uuid")},
+	}
 
-	if owner == "" || uuid == "" {
+	if params.Post.Owner == "" || params.Post.UUID == "" {
 		api.WriteError(w, http.StatusBadRequest, "invalid owner or uuid")
 		return
 	}
 
-	post, err := s.s.GetPost(r.Context(), storage.PostID{Owner: owner, UUID: uuid})
-	if err != nil {
-		if errors.Is(err, storage.ErrNotFound) {
-			api.WriteError(w, http.StatusNotFound, "post not found")
+	q := r.URL.Query()
+
+	var err error
+	if params.Limit, err = extractLimitFromQuery(q); err != nil {
+		api.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if c := q.Get("cursor"); c != "" {
+		var lc likesCursor
+		if err := s.cursor.decode(c, &lc); err != nil {
+			api.WriteError(w, http.StatusBadRequest, err.Error())
 			return
 		}
-		api.WriteInternalErrorf(r.Context(), w, "failed to get post: %s", err.Error())
+
+		params.After = lc.toStorage()
+	}
+
+	switch q.Get("weight") {
+	case "1":
+		v := community.LikeWeight_LIKE_WEIGHT_UP
+		params.Weight = &v
+	case "-1":
+		v := community.LikeWeight_LIKE_WEIGHT_DOWN
+		params.Weight = &v
+	case "":
+	default:
+		api.WriteError(w, http.StatusBadRequest, "invalid weight")
+		return
+	}
+
+	likes, err := s.s.ListLikes(r.Context(), &params)
+	if err != nil {
+		api.WriteInternalErrorf(r.Context(), w, "failed to list likes: %s", err.Error())
 		return
 	}
 
-	pID := storage.PostID{Owner: post.Owner, UUID: post.UUID}
-	stats, err := s.s.GetPostStats(r.Context(), pID)
+	resp := ListLikesResponse{
+		Likes: make([]*Like, len(likes)),
+	}
+	for i, v := range likes {
+		resp.Likes[i] = &Like{
+			LikedBy: v.LikedBy,
+			Weight:  v.Weight,
+			LikedAt: toUnixTimestamp(v.LikedAt),
+		}
+	}
+
+	// the page is full, so there could be more likes
+	if len(likes) > 0 && len(likes) == int(params.Limit) {
+		if resp.NextCursor, err = s.cursor.encode(newLikesCursor(likes[len(likes)-1])); err != nil {
+			api.WriteInternalErrorf(r.Context(), w, "failed to encode cursor: %s", err.Error())
+			return
+		}
+	}
+
+	api.WriteOK(w, http.StatusOK, resp)
+}
+
+func (s server) getSharePostBySlug(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /posts/{slug} Community GetPostBySlug
+	//
+	// Get post by slug.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: slug
+	//   in: path
+	//   required: true
+	//   type: string
+	// responses:
+	//   '200':
+	//     description: Post
+	//     schema:
+	//       "$ref": "#/definitions/SharePost"
+	//   '404':
+	//     description: post not found
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '500':
+	//     description: internal server error
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+
+	slug := chi.URLParam(r, "slug")
+
+	post, err := s.s.GetPostBySlug(r.Context(), slug)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			api.WriteError(w, http.StatusNotFound, "post not found")
+			return
+		}
+		api.WriteInternalErrorf(r.Context(), w, "failed to get post: %s", err.Error())
+		return
+	}
+
+	api.WriteOK(w, http.StatusOK, SharePost{
+		UUID:  post.UUID,
+		Owner: post.Owner,
+		Title: post.Title,
+	})
+}
+
+func (s server) getPost(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /posts/{owner}/{uuid} Community GetPostByID
+	//
+	// Get post by owner and uuid.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   required: true
+	//   type: string
+	// - name: uuid
+	//   in: path
+	//   required: true
+	//   type: string
+	// - name: requestedBy
+	//   in: query
+	//   description: adds liked flag to response
+	//   required: false
+	//   example: decentr1ltx6yymrs8eq4nmnhzfzxj6tspjuymh8mgd6gz
+	// responses:
+	//   '200':
+	//     description: Post
+	//     schema:
+	//       "$ref": "#/definitions/GetPostResponse"
+	//   '400':
+	//     description: bad request
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '404':
+	//     description: post not found
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '500':
+	//     description: internal server error
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+
+	owner, uuid := chi.URLParam(r, "owner"), chi.URLParam(r, "uuid")
+
+	if owner == "" || uuid == "" {
+		api.WriteError(w, http.StatusBadRequest, "invalid owner or uuid")
+		return
+	}
+
+	post, err := s.s.GetPost(r.Context(), storage.PostID{Owner: owner, UUID: uuid})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			api.WriteError(w, http.StatusNotFound, "post not found")
+			return
+		}
+		api.WriteInternalErrorf(r.Context(), w, "failed to get post: %s", err.Error())
+		return
+	}
+
+	pID := storage.PostID{Owner: post.Owner, UUID: post.UUID}
+	stats, err := s.s.GetPostStats(r.Context(), pID)
+	if err != nil {
+		api.WriteInternalErrorf(r.Context(), w, "failed to get stats: %s", err.Error())
+		return
+	}
+
+	resp := GetPostResponse{
+		Post: *toAPIPost(post),
+	}
+
+	profileStats, err := s.s.GetProfileStats(r.Context(), post.Owner)
+	if err != nil {
+		api.WriteInternalErrorf(r.Context(), w, "failed to get profile: %s", err.Error())
+		return
+	}
+
+	resp.ProfileStats = toAPIProfileStats(profileStats[0])
+
+	if s, ok := stats[pID]; ok {
+		resp.Stats = toAPIStats(s)
+	}
+
+	if requestedBy := r.URL.Query().Get("requestedBy"); requestedBy != "" {
+		liked, err := s.s.GetLikes(r.Context(), requestedBy, pID)
+		if err != nil {
+			api.WriteInternalErrorf(r.Context(), w, "failed to get like: %s", err.Error())
+			return
+		}
+
+		v := liked[pID]
+		resp.Post.LikeWeight = &v
+	}
+
+	api.WriteOK(w, http.StatusOK, resp)
+}
+
+func (s server) listRelatedPosts(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /posts/{owner}/{uuid}/related Community ListRelatedPosts
+	//
+	// Returns posts liked by the same profiles that liked the post, the most common first.
+	// The list is completed by the best posts of the post's category. Relations are updated periodically.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   required: true
+	//   type: string
+	// - name: uuid
+	//   in: path
+	//   required: true
+	//   type: string
+	// - name: limit
+	//   description: limits count of returned posts
+	//   in: query
+	//   required: false
+	//   default: 20
+	//   minimum: 1
+	//   maximum: 100
+	// - name: requestedBy
+	//   in: query
+	//   description: adds liked flag to response
+	//   required: false
+	//   example: decentr1ltx6yymrs8eq4nmnhzfzxj6tspjuymh8mgd6gz
+	// responses:
+	//   '200':
+	//     description: Posts
+	//     schema:
+	//       "$ref": "#/definitions/ListPostsResponse"
+	//   '400':
+	//     description: bad request
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '404':
+	//     description: post not found
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '500':
+	//     description: internal server error
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+
+	id := storage.PostID{Owner: chi.URLParam(r, "owner"), UUID: chi.URLParam(r, "uuid")}
+
+	if id.Owner == "" || id.UUID == "" {
+		api.WriteError(w, http.StatusBadRequest, "invalid owner or uuid")
+		return
+	}
+
+	q := r.URL.Query()
+
+	limit, err := extractLimitFromQuery(q)
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	post, err := s.s.GetPost(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			api.WriteError(w, http.StatusNotFound, "post not found")
+			return
+		}
+		api.WriteInternalErrorf(r.Context(), w, "failed to get post: %s", err.Error())
+		return
+	}
+
+	posts, err := s.s.ListRelatedPosts(r.Context(), id, limit)
+	if err != nil {
+		api.WriteInternalErrorf(r.Context(), w, "failed to list related posts: %s", err.Error())
+		return
+	}
+
+	if len(posts) < int(limit) {
+		// the post itself and related posts could be listed too, so they are skipped
+		top, err := s.s.ListPosts(r.Context(), &storage.ListPostsParams{
+			SortBy:   storage.BestSortType,
+			OrderBy:  storage.DescendingOrder,
+			Limit:    limit + uint16(len(posts)) + 1,
+			Category: &post.Category,
+		})
+		if err != nil {
+			api.WriteInternalErrorf(r.Context(), w, "failed to list category posts: %s", err.Error())
+			return
+		}
+
+		listed := map[storage.PostID]bool{id: true}
+		for _, v := range posts {
+			listed[storage.PostID{Owner: v.Owner, UUID: v.UUID}] = true
+		}
+
+		for _, v := range top {
+			if len(posts) == int(limit) {
+				break
+			}
+
+			if pID := (storage.PostID{Owner: v.Owner, UUID: v.UUID}); !listed[pID] {
+				listed[pID] = true
+				posts = append(posts, v)
+			}
+		}
+	}
+
+	resp, err := s.getListPostsResponse(r.Context(), posts, q.Get("requestedBy"))
+	if err != nil {
+		api.WriteInternalErrorf(r.Context(), w, "failed to get posts meta: %s", err.Error())
+		return
+	}
+
+	api.WriteOK(w, http.StatusOK, resp)
+}
+
+func (s server) getProfileStats(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /profiles/{address}/stats Profiles GetProfileStats
+	//
+	// Get pdv stats by address.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: address
+	//   in: path
+	//   required: true
+	//   type: string
+	// responses:
+	//   '200':
+	//     description: Profile stats
+	//     schema:
+	//       "$ref": "#/definitions/ProfileStats"
+	//   '404':
+	//     description: profile not found
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '400':
+	//     description: bad request
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '500':
+	//     description: internal server error
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+
+	address := chi.URLParam(r, "address")
+
+	if address == "" {
+		api.WriteError(w, http.StatusBadRequest, "invalid address")
+		return
+	}
+
+	stats, err := s.s.GetProfileStats(r.Context(), address)
+	if err != nil {
+		api.WriteInternalErrorf(r.Context(), w, "failed to get profile stats: %s", err.Error())
+		return
+	}
+
+	api.WriteOK(w, http.StatusOK, toAPIProfileStats(stats[0]))
+}
+
+func (s server) getFollowersStats(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /profiles/{address}/followers/stats Profiles GetFollowersStats
+	//
+	// Get followers count history by address.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: address
+	//   in: path
+	//   required: true
+	//   type: string
+	// responses:
+	//   '200':
+	//     description: Followers stats
+	//     schema:
+	//       "$ref": "#/definitions/FollowersStats"
+	//   '400':
+	//     description: bad request
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '500':
+	//     description: internal server error
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+
+	address := chi.URLParam(r, "address")
+
+	if address == "" {
+		api.WriteError(w, http.StatusBadRequest, "invalid address")
+		return
+	}
+
+	stats, err := s.s.GetFollowersStats(r.Context(), address)
+	if err != nil {
+		api.WriteInternalErrorf(r.Context(), w, "failed to get followers stats: %s", err.Error())
+		return
+	}
+
+	api.WriteOK(w, http.StatusOK, toAPIFollowersStats(stats))
+}
+
+func (s server) listFollowers(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /profiles/{address}/followers Profiles ListFollowers
+	//
+	// Returns followers of the profile, the latest first.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: address
+	//   in: path
+	//   required: true
+	//   type: string
+	// - name: limit
+	//   description: limits count of returned followers
+	//   in: query
+	//   required: false
+	//   default: 20
+	//   minimum: 1
+	//   maximum: 100
+	// - name: cursor
+	//   description: sets position after which followers will be returned, it's nextCursor of previous page
+	//   in: query
+	//   required: false
+	// - name: requestedBy
+	//   in: query
+	//   description: adds followed flag to response
+	//   required: false
+	//   example: decentr1ltx6yymrs8eq4nmnhzfzxj6tspjuymh8mgd6gz
+	// responses:
+	//   '200':
+	//     description: Follows
+	//     schema:
+	//       "$ref": "#/definitions/ListFollowsResponse"
+	//   '400':
+	//     description: bad request
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '500':
+	//     description: internal server error
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+
+	s.listFollows(w, r, s.s.ListFollowers, func(c *storage.FollowsCount) uint32 {
+		return c.Followers
+	}, func(f *storage.Follow) string {
+		return f.Follower
+	})
+}
+
+func (s server) listFollowing(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /profiles/{address}/following Profiles ListFollowing
+	//
+	// Returns profiles followed by the profile, the latest first.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: address
+	//   in: path
+	//   required: true
+	//   type: string
+	// - name: limit
+	//   description: limits count of returned profiles
+	//   in: query
+	//   required: false
+	//   default: 20
+	//   minimum: 1
+	//   maximum: 100
+	// - name: cursor
+	//   description: sets position after which profiles will be returned, it's nextCursor of previous page
+	//   in: query
+	//   required: false
+	// - name: requestedBy
+	//   in: query
+	//   description: adds followed flag to response
+	//   required: false
+	//   example: decentr1ltx6yymrs8eq4nmnhzfzxj6tspjuymh8mgd6gz
+	// responses:
+	//   '200':
+	//     description: Follows
+	//     schema:
+	//       "$ref": "#/definitions/ListFollowsResponse"
+	//   '400':
+	//     description: bad request
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '500':
+	//     description: internal server error
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+
+	s.listFollows(w, r, s.s.ListFollowing, func(c *storage.FollowsCount) uint32 {
+		return c.Following
+	}, func(f *storage.Follow) string {
+		return f.Followee
+	})
+}
+
+func (s server) listFollows(
+	w http.ResponseWriter, r *http.Request,
+	list func(ctx context.Context, p *storage.ListFollowsParams) ([]*storage.Follow, error),
+	total func(c *storage.FollowsCount) uint32,
+	listed func(f *storage.Follow) string,
+) {
+	params := storage.ListFollowsParams{
+		Address: chi.URLParam(r, "address"),
+	}
+
+	if params.Address == "" {
+		api.WriteError(w, http.StatusBadRequest, "invalid address")
+		return
+	}
+
+	var err error
+	if params.Limit, err = extractLimitFromQuery(r.URL.Query()); err != nil {
+		api.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if c := r.URL.Query().Get("cursor"); c != "" {
+		var fc followsCursor
+		if err := s.cursor.decode(c, &fc); err != nil {
+			api.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		params.After = fc.toStorage()
+	}
+
+	follows, err := list(r.Context(), &params)
+	if err != nil {
+		api.WriteInternalErrorf(r.Context(), w, "failed to list follows: %s", err.Error())
+		return
+	}
+
+	count, err := s.s.GetFollowsCount(r.Context(), params.Address)
+	if err != nil {
+		api.WriteInternalErrorf(r.Context(), w, "failed to get follows count: %s", err.Error())
+		return
+	}
+
+	resp := ListFollowsResponse{
+		Total:   total(count),
+		Follows: make([]*Follow, len(follows)),
+	}
+
+	addresses := make([]string, len(follows))
+	for i, v := range follows {
+		addresses[i] = listed(v)
+		resp.Follows[i] = &Follow{
+			Address:    addresses[i],
+			FollowedAt: toUnixTimestamp(v.CreatedAt),
+		}
+	}
+
+	// the page is full, so there could be more follows
+	if len(follows) > 0 && len(follows) == int(params.Limit) {
+		last := follows[len(follows)-1]
+		if resp.NextCursor, err = s.cursor.encode(newFollowsCursor(last, listed(last))); err != nil {
+			api.WriteInternalErrorf(r.Context(), w, "failed to encode cursor: %s", err.Error())
+			return
+		}
+	}
+
+	if requestedBy := r.URL.Query().Get("requestedBy"); requestedBy != "" {
+		followed, err := s.s.GetFollowed(r.Context(), requestedBy, addresses...)
+		if err != nil {
+			api.WriteInternalErrorf(r.Context(), w, "failed to get followed: %s", err.Error())
+			return
+		}
+
+		for i, v := range addresses {
+			f := followed[v]
+			resp.Follows[i].Followed = &f
+		}
+	}
+
+	api.WriteOK(w, http.StatusOK, resp)
+}
+
+func (s server) listFollowSuggestions(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /profiles/{address}/suggestions Profiles ListFollowSuggestions
+	//
+	// Returns profiles suggested to follow: followed by the profile's followees and authors of posts liked by the profile.
+	// Already followed profiles are excluded, suggestions with more reasons are returned first.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: address
+	//   in: path
+	//   required: true
+	//   type: string
+	// - name: limit
+	//   description: limits count of returned suggestions
+	//   in: query
+	//   required: false
+	//   default: 20
+	//   minimum: 1
+	//   maximum: 100
+	// responses:
+	//   '200':
+	//     description: Suggestions
+	//     schema:
+	//       "$ref": "#/definitions/ListFollowSuggestionsResponse"
+	//   '400':
+	//     description: bad request
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '500':
+	//     description: internal server error
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+
+	limit, err := extractLimitFromQuery(r.URL.Query())
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	suggestions, err := s.s.ListFollowSuggestions(r.Context(), chi.URLParam(r, "address"), limit)
+	if err != nil {
+		api.WriteInternalErrorf(r.Context(), w, "failed to list suggestions: %s", err.Error())
+		return
+	}
+
+	resp := ListFollowSuggestionsResponse{
+		Suggestions: make([]*FollowSuggestion, len(suggestions)),
+	}
+	for i, v := range suggestions {
+		resp.Suggestions[i] = &FollowSuggestion{
+			Address:             v.Address,
+			FollowedByFollowees: v.FollowedByFollowees,
+			LikedPosts:          v.LikedPosts,
+		}
+	}
+
+	api.WriteOK(w, http.StatusOK, resp)
+}
+
+func (s server) getBalance(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /profiles/{address}/balance Profiles GetBalance
+	//
+	// Get token balances by address with daily history.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: address
+	//   in: path
+	//   required: true
+	//   type: string
+	// responses:
+	//   '200':
+	//     description: Balances
+	//     schema:
+	//       "$ref": "#/definitions/Balances"
+	//   '400':
+	//     description: bad request
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '500':
+	//     description: internal server error
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+
+	address := chi.URLParam(r, "address")
+
+	if address == "" {
+		api.WriteError(w, http.StatusBadRequest, "invalid address")
+		return
+	}
+
+	balances, err := s.s.GetBalances(r.Context(), address)
+	if err != nil {
+		api.WriteInternalErrorf(r.Context(), w, "failed to get balances: %s", err.Error())
+		return
+	}
+
+	api.WriteOK(w, http.StatusOK, toAPIBalances(balances))
+}
+
+func (s server) listPDV(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /profiles/{address}/pdv Profiles ListPDV
+	//
+	// Returns pdv changes of the profile with their sources, the latest first.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: address
+	//   in: path
+	//   required: true
+	//   type: string
+	// - name: limit
+	//   description: limits count of returned changes
+	//   in: query
+	//   required: false
+	//   default: 20
+	//   minimum: 1
+	//   maximum: 100
+	// - name: cursor
+	//   description: sets position after which changes will be returned, it's nextCursor of previous page
+	//   in: query
+	//   required: false
+	// responses:
+	//   '200':
+	//     description: PDV changes
+	//     schema:
+	//       "$ref": "#/definitions/ListPDVResponse"
+	//   '400':
+	//     description: bad request
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '500':
+	//     description: internal server error
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+
+	params := storage.ListPDVParams{
+		Address: chi.URLParam(r, "address"),
+	}
+
+	if params.Address == "" {
+		api.WriteError(w, http.StatusBadRequest, "invalid address")
+		return
+	}
+
+	var err error
+	if params.Limit, err = extractLimitFromQuery(r.URL.Query()); err != nil {
+		api.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if c := r.URL.Query().Get("cursor"); c != "" {
+		var pc pdvCursor
+		if err := s.cursor.decode(c, &pc); err != nil {
+			api.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		params.After = pc.toStorage()
+	}
+
+	pdv, err := s.s.ListPDV(r.Context(), &params)
+	if err != nil {
+		api.WriteInternalErrorf(r.Context(), w, "failed to list pdv: %s", err.Error())
+		return
+	}
+
+	resp := ListPDVResponse{
+		PDV: make([]*PDV, len(pdv)),
+	}
+	for i, v := range pdv {
+		resp.PDV[i] = toAPIPDV(v)
+	}
+
+	// the page is full, so there could be more changes
+	if len(pdv) > 0 && len(pdv) == int(params.Limit) {
+		if resp.NextCursor, err = s.cursor.encode(newPDVCursor(pdv[len(pdv)-1])); err != nil {
+			api.WriteInternalErrorf(r.Context(), w, "failed to encode cursor: %s", err.Error())
+			return
+		}
+	}
+
+	api.WriteOK(w, http.StatusOK, resp)
+}
+
+func (s server) getDDVStats(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /ddv/stats DDV GetDDVStats
+	//
+	// Returns DDV stats.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// responses:
+	//   '200':
+	//     description: Stats
+	//     schema:
+	//       "$ref": "#/definitions/DDVStats"
+	//   '500':
+	//     description: internal server error
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+
+	totalStats, err := s.s.GetDecentrStats(r.Context())
+	if err != nil {
+		api.WriteInternalErrorf(r.Context(), w, "failed to get total stats: %s", err.Error())
+		return
+	}
+
+	ddvStats, err := s.s.GetDDVStats(r.Context())
+	if err != nil {
+		api.WriteInternalErrorf(r.Context(), w, "failed to get DDV stats: %s", err.Error())
+		return
+	}
+
+	transformStatsAsGrowth(ddvStats, totalStats.DDV)
+
+	stats := make([]StatsItem, 0, len(ddvStats))
+	for _, item := range ddvStats {
+		stats = append(stats, StatsItem{
+			Date:  item.Date.Format("2006-01-02"),
+			Value: denominate(item.Value),
+		})
+	}
+
+	api.WriteOK(w, http.StatusOK, DDVStats{
+		Total: denominate(totalStats.DDV),
+		Stats: stats,
+	})
+}
+
+func transformStatsAsGrowth(stats []*storage.DDVStatsItem, total int64) {
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Date.Before(stats[j].Date)
+	})
+
+	for i := len(stats) - 1; i >= 0; i-- {
+		v := stats[i].Value
+		stats[i].Value = total
+		total -= v
+	}
+}
+
+func (s server) getDecentrStats(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /profiles/stats Profiles GetDecentrStats
+	//
+	// Returns decentr stats.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// responses:
+	//   '200':
+	//     description: Stats
+	//     schema:
+	//       "$ref": "#/definitions/DecentrStats"
+	//   '500':
+	//     description: internal server error
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+
+	stats, err := s.s.GetDecentrStats(r.Context())
+	if err != nil {
+		api.WriteInternalErrorf(r.Context(), w, "failed to get all users stats: %s", err.Error())
+		return
+	}
+
+	api.WriteOK(w, http.StatusOK, DecentrStats{
+		ADV: denominateFloat(stats.ADV),
+		DDV: denominate(stats.DDV),
+	})
+}
+
+func (s server) listCategories(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /categories Community ListCategories
+	//
+	// Returns every posts category with posts count, active authors, received likes and daily created posts.
+	// The stats are updated periodically.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// responses:
+	//   '200':
+	//     description: Categories
+	//     schema:
+	//       "$ref": "#/definitions/ListCategoriesResponse"
+	//   '500':
+	//     description: internal server error
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+
+	stats, err := s.s.GetCategoryStats(r.Context())
+	if err != nil {
+		api.WriteInternalErrorf(r.Context(), w, "failed to get categories stats: %s", err.Error())
+		return
+	}
+
+	api.WriteOK(w, http.StatusOK, toAPICategories(stats))
+}
+
+func (s server) getLeaderboard(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /leaderboard Profiles GetLeaderboard
+	//
+	// Returns profiles ranked by earned pdv, received likes or created posts over the period ending today.
+	// The leaderboard is updated periodically.
+	//
+	// ---
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: metric
+	//   description: sets value profiles are ranked by
+	//   in: query
+	//   required: false
+	//   default: pdv
+	//   type: string
+	//   enum: [pdv, likes, posts]
+	// - name: window
+	//   description: sets period ranked values are counted over, month is 30 days
+	//   in: query
+	//   required: false
+	//   default: all
+	//   type: string
+	//   enum: [day, week, month, all]
+	// - name: limit
+	//   description: limits count of returned items
+	//   in: query
+	//   required: false
+	//   default: 20
+	//   minimum: 1
+	//   maximum: 100
+	// - name: cursor
+	//   description: sets position after which items will be returned, it's nextCursor of previous page
+	//   in: query
+	//   required: false
+	// - name: address
+	//   description: adds rank of the address to response
+	//   in: query
+	//   required: false
+	//   example: decentr1ltx6yymrs8eq4nmnhzfzxj6tspjuymh8mgd6gz
+	// responses:
+	//   '200':
+	//     description: Leaderboard
+	//     schema:
+	//       "$ref": "#/definitions/LeaderboardResponse"
+	//   '400':
+	//     description: bad request
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+	//   '500':
+	//     description: internal server error
+	//     schema:
+	//       "$ref": "#/definitions/Error"
+
+	q := r.URL.Query()
+
+	params, err := extractListLeaderboardParamsFromQuery(q)
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if c := q.Get("cursor"); c != "" {
+		var lc leaderboardCursor
+		if err := s.cursor.decode(c, &lc); err != nil {
+			api.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if lc.Metric != params.Metric || lc.Period != params.Period {
+			api.WriteError(w, http.StatusBadRequest, errInvalidCursor.Error())
+			return
+		}
+
+		params.After = lc.toStorage()
+	}
+
+	items, err := s.s.ListLeaderboard(r.Context(), params)
 	if err != nil {
-		api.WriteInternalErrorf(r.Context(), w, "failed to get stats: %s", err.Error())
+		api.WriteInternalErrorf(r.Context(), w, "failed to list leaderboard: %s", err.Error())
 		return
 	}
 
-	resp := GetPostResponse{
-		Post: *toAPIPost(post),
+	resp := LeaderboardResponse{
+		Items: make([]*LeaderboardItem, len(items)),
 	}
-
-	profileStats, err := s.s.GetProfileStats(r.Context(), post.Owner)
-	if err != nil {
-		api.WriteInternalErrorf(r.Context(), w, "failed to get profile: %s", err.Error())
-		return
+	for i, v := range items {
+		resp.Items[i] = toAPILeaderboardItem(v, params.Metric)
 	}
 
-	resp.ProfileStats = toAPIProfileStats(profileStats[0])
+	if address := q.Get("address"); address != "" {
+		item, err := s.s.GetLeaderboardItem(r.Context(), params.Metric, params.Period, address)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			api.WriteInternalErrorf(r.Context(), w, "failed to get leaderboard item: %s", err.Error())
+			return
+		}
 
-	if s, ok := stats[pID]; ok {
-		resp.Stats = toAPIStats(s)
+		if item != nil {
+			resp.AddressItem = toAPILeaderboardItem(item, params.Metric)
+		}
 	}
 
-	if requestedBy := r.URL.Query().Get("requestedBy"); requestedBy != "" {
-		liked, err := s.s.GetLikes(r.Context(), requestedBy, pID)
-		if err != nil {
-			api.WriteInternalErrorf(r.Context(), w, "failed to get like: %s", err.Error())
+	// the page is full, so there could be more items
+	if len(items) > 0 && len(items) == int(params.Limit) {
+		c := newLeaderboardCursor(items[len(items)-1], params.Metric, params.Period)
+		if resp.NextCursor, err = s.cursor.encode(c); err != nil {
+			api.WriteInternalErrorf(r.Context(), w, "failed to encode cursor: %s", err.Error())
 			return
 		}
-
-		v := liked[pID]
-		resp.Post.LikeWeight = &v
 	}
 
 	api.WriteOK(w, http.StatusOK, resp)
 }
 
-func (s server) getProfileStats(w http.ResponseWriter, r *http.Request) {
-	// swagger:operation GET /profiles/{address}/stats Profiles GetProfileStats
+func (s server) listTokenMovements(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /supply/movements Supply ListTokenMovements
 	//
-	// Get pdv stats by address.
+	// Returns mints and burns of tokens, the latest first.
 	//
 	// ---
 	// produces:
 	// - application/json
 	// parameters:
+	// - name: denom
+	//   description: filters movements by denom
+	//   in: query
+	//   required: false
+	//   example: udec
 	// - name: address
-	//   in: path
-	//   required: true
-	//   type: string
+	//   description: filters movements by address
+	//   in: query
+	//   required: false
+	//   example: decentr1ltx6yymrs8eq4nmnhzfzxj6tspjuymh8mgd6gz
+	// - name: limit
+	//   description: limits count of returned movements
+	//   in: query
+	//   required: false
+	//   default: 20
+	//   minimum: 1
+	//   maximum: 100
+	// - name: cursor
+	//   description: sets position after which movements will be returned, it's nextCursor of previous page
+	//   in: query
+	//   required: false
 	// responses:
 	//   '200':
-	//     description: Profile stats
-	//     schema:
-	//       "$ref": "#/definitions/ProfileStats"
-	//   '404':
-	//     description: profile not found
+	//     description: Movements
 	//     schema:
-	//       "$ref": "#/definitions/Error"
+	//       "$ref": "#/definitions/ListTokenMovementsResponse"
 	//   '400':
 	//     description: bad request
 	//     schema:
@@ -343,111 +1598,111 @@ func (s server) getProfileStats(w http.ResponseWriter, r *http.Request) {
 	//     schema:
 	//       "$ref": "#/definitions/Error"
 
-	address := chi.URLParam(r, "address")
-
-	if address == "" {
-		api.WriteError(w, http.StatusBadRequest, "invalid address")
-		return
-	}
-
-	stats, err := s.s.GetProfileStats(r.Context(), address)
+	params, err := extractListTokenMovementsParamsFromQuery(r.URL.Query())
 	if err != nil {
-		api.WriteInternalErrorf(r.Context(), w, "failed to get profile stats: %s", err.Error())
+		api.WriteError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	api.WriteOK(w, http.StatusOK, toAPIProfileStats(stats[0]))
-}
-
-func (s server) getDDVStats(w http.ResponseWriter, r *http.Request) {
-	// swagger:operation GET /ddv/stats DDV GetDDVStats
-	//
-	// Returns DDV stats.
-	//
-	// ---
-	// produces:
-	// - application/json
-	// responses:
-	//   '200':
-	//     description: Stats
-	//     schema:
-	//       "$ref": "#/definitions/DDVStats"
-	//   '500':
-	//     description: internal server error
-	//     schema:
-	//       "$ref": "#/definitions/Error"
+	if c := r.URL.Query().Get("cursor"); c != "" {
+		var mc tokenMovementsCursor
+		if err := s.cursor.decode(c, &mc); err != nil {
+			api.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 
-	totalStats, err := s.s.GetDecentrStats(r.Context())
-	if err != nil {
-		api.WriteInternalErrorf(r.Context(), w, "failed to get total stats: %s", err.Error())
-		return
+		params.After = mc.toStorage()
 	}
 
-	ddvStats, err := s.s.GetDDVStats(r.Context())
+	movements, err := s.s.ListTokenMovements(r.Context(), params)
 	if err != nil {
-		api.WriteInternalErrorf(r.Context(), w, "failed to get DDV stats: %s", err.Error())
+		api.WriteInternalErrorf(r.Context(), w, "failed to list token movements: %s", err.Error())
 		return
 	}
 
-	transformStatsAsGrowth(ddvStats, totalStats.DDV)
-
-	stats := make([]StatsItem, 0, len(ddvStats))
-	for _, item := range ddvStats {
-		stats = append(stats, StatsItem{
-			Date:  item.Date.Format("2006-01-02"),
-			Value: denominate(item.Value),
-		})
+	resp := ListTokenMovementsResponse{
+		Movements: make([]*TokenMovement, len(movements)),
+	}
+	for i, v := range movements {
+		resp.Movements[i] = &TokenMovement{
+			ID:        v.ID,
+			Type:      string(v.Type),
+			Address:   v.Address,
+			Denom:     v.Denom,
+			Amount:    v.Amount,
+			Timestamp: uint64(v.Timestamp.Unix()),
+		}
 	}
 
-	api.WriteOK(w, http.StatusOK, DDVStats{
-		Total: denominate(totalStats.DDV),
-		Stats: stats,
-	})
-}
-
-func transformStatsAsGrowth(stats []*storage.DDVStatsItem, total int64) {
-	sort.Slice(stats, func(i, j int) bool {
-		return stats[i].Date.Before(stats[j].Date)
-	})
-
-	for i := len(stats) - 1; i >= 0; i-- {
-		v := stats[i].Value
-		stats[i].Value = total
-		total -= v
+	// the page is full, so there could be more movements
+	if len(movements) > 0 && len(movements) == int(params.Limit) {
+		c := newTokenMovementsCursor(movements[len(movements)-1])
+		if resp.NextCursor, err = s.cursor.encode(c); err != nil {
+			api.WriteInternalErrorf(r.Context(), w, "failed to encode cursor: %s", err.Error())
+			return
+		}
 	}
+
+	api.WriteOK(w, http.StatusOK, resp)
 }
 
-func (s server) getDecentrStats(w http.ResponseWriter, r *http.Request) {
-	// swagger:operation GET /profiles/stats Profiles GetDecentrStats
+func (s server) getSupplyStats(w http.ResponseWriter, r *http.Request) {
+	// swagger:operation GET /supply/stats Supply GetSupplyStats
 	//
-	// Returns decentr stats.
+	// Returns daily amounts of minted and burned tokens and net supply change over time.
 	//
 	// ---
 	// produces:
 	// - application/json
+	// parameters:
+	// - name: denom
+	//   description: sets denom of tokens
+	//   in: query
+	//   required: false
+	//   default: udec
+	//   example: udec
 	// responses:
 	//   '200':
 	//     description: Stats
 	//     schema:
-	//       "$ref": "#/definitions/DecentrStats"
+	//       "$ref": "#/definitions/SupplyStats"
 	//   '500':
 	//     description: internal server error
 	//     schema:
 	//       "$ref": "#/definitions/Error"
 
-	stats, err := s.s.GetDecentrStats(r.Context())
+	denom := r.URL.Query().Get("denom")
+	if denom == "" {
+		denom = defaultDenom
+	}
+
+	items, err := s.s.GetSupplyStats(r.Context(), denom)
 	if err != nil {
-		api.WriteInternalErrorf(r.Context(), w, "failed to get all users stats: %s", err.Error())
+		api.WriteInternalErrorf(r.Context(), w, "failed to get supply stats: %s", err.Error())
 		return
 	}
 
-	api.WriteOK(w, http.StatusOK, DecentrStats{
-		ADV: denominateFloat(stats.ADV),
-		DDV: denominate(stats.DDV),
-	})
+	resp := SupplyStats{
+		Denom: denom,
+		Stats: make([]SupplyStatsItem, len(items)),
+	}
+	for i, v := range items {
+		resp.Minted += v.Minted
+		resp.Burned += v.Burned
+		resp.Net = resp.Minted - resp.Burned
+
+		resp.Stats[i] = SupplyStatsItem{
+			Date:         v.Date.Format("2006-01-02"),
+			Minted:       v.Minted,
+			Burned:       v.Burned,
+			Net:          v.Minted - v.Burned,
+			SupplyChange: resp.Net,
+		}
+	}
+
+	api.WriteOK(w, http.StatusOK, resp)
 }
 
-// nolint: gocyclo
 func extractListParamsFromQuery(q url.Values) (*storage.ListPostsParams, error) {
 	out := storage.ListPostsParams{
 		SortBy:  storage.CreatedAtSortType,
@@ -465,6 +1720,14 @@ func extractListParamsFromQuery(q url.Values) (*storage.ListPostsParams, error)
 		out.SortBy = storage.DislikesSortType
 	case "pdv":
 		out.SortBy = storage.PDVSortType
+	case "hot":
+		out.SortBy = storage.HotSortType
+	case "rising":
+		out.SortBy = storage.RisingSortType
+	case "best":
+		out.SortBy = storage.BestSortType
+	case "controversial":
+		out.SortBy = storage.ControversialSortType
 	case "":
 	default:
 		return nil, fmt.Errorf("%w: invalid sortBy", errInvalidRequest)
@@ -525,17 +1788,8 @@ func extractListParamsFromQuery(q url.Values) (*storage.ListPostsParams, error)
 		out.FollowedBy = &s
 	}
 
-	if s := q.Get("after"); s != "" {
-		p := strings.Split(s, "/")
-
-		if len(p) != 2 {
-			return nil, fmt.Errorf("%w: invalid post id", errInvalidRequest)
-		}
-
-		out.After = &storage.PostID{
-			Owner: p[0],
-			UUID:  p[1],
-		}
+	if s := q.Get("q"); strings.TrimSpace(s) != "" {
+		out.Query = &s
 	}
 
 	if s := q.Get("from"); s != "" {
@@ -559,6 +1813,89 @@ func extractListParamsFromQuery(q url.Values) (*storage.ListPostsParams, error)
 	return &out, nil
 }
 
+// parsePostID parses full form post ID (owner/uuid).
+func parsePostID(s string) (storage.PostID, error) {
+	p := strings.Split(s, "/")
+
+	if len(p) != 2 {
+		return storage.PostID{}, fmt.Errorf("%w: invalid post id", errInvalidRequest)
+	}
+
+	return storage.PostID{
+		Owner: p[0],
+		UUID:  p[1],
+	}, nil
+}
+
+func extractListTokenMovementsParamsFromQuery(q url.Values) (*storage.ListTokenMovementsParams, error) {
+	var out storage.ListTokenMovementsParams
+
+	if s := q.Get("denom"); s != "" {
+		out.Denom = &s
+	}
+
+	if s := q.Get("address"); s != "" {
+		out.Address = &s
+	}
+
+	var err error
+	if out.Limit, err = extractLimitFromQuery(q); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+func extractListLeaderboardParamsFromQuery(q url.Values) (*storage.ListLeaderboardParams, error) {
+	out := storage.ListLeaderboardParams{
+		Metric: storage.PDVLeaderboardMetric,
+		Period: storage.AllLeaderboardPeriod,
+	}
+
+	switch m := storage.LeaderboardMetric(q.Get("metric")); m {
+	case storage.PDVLeaderboardMetric, storage.LikesLeaderboardMetric, storage.PostsLeaderboardMetric:
+		out.Metric = m
+	case "":
+	default:
+		return nil, fmt.Errorf("%w: invalid metric", errInvalidRequest)
+	}
+
+	switch p := storage.LeaderboardPeriod(q.Get("window")); p {
+	case storage.DayLeaderboardPeriod, storage.WeekLeaderboardPeriod,
+		storage.MonthLeaderboardPeriod, storage.AllLeaderboardPeriod:
+		out.Period = p
+	case "":
+	default:
+		return nil, fmt.Errorf("%w: invalid window", errInvalidRequest)
+	}
+
+	var err error
+	if out.Limit, err = extractLimitFromQuery(q); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// extractLimitFromQuery returns limit of entities to be returned.
+func extractLimitFromQuery(q url.Values) (uint16, error) {
+	limit := uint16(defaultLimit)
+	if s := q.Get("limit"); s != "" {
+		v, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: failed to parse limit", errInvalidRequest)
+		}
+
+		if v == 0 || v > maxLimit {
+			return 0, fmt.Errorf("%w: invalid limit", errInvalidRequest)
+		}
+
+		limit = uint16(v)
+	}
+
+	return limit, nil
+}
+
 func extractProfileIDsFromPosts(p []*storage.Post) []string {
 	out := make([]string, 0, len(p))
 	m := make(map[string]struct{}, len(p))
@@ -638,6 +1975,93 @@ func toAPIPost(p *storage.Post) *Post {
 	}
 }
 
+func toAPIPDV(p *storage.PDV) *PDV {
+	out := PDV{
+		ID:           p.ID,
+		Amount:       denominate(p.Amount),
+		Source:       string(p.Source),
+		Counterparty: p.Counterparty,
+		Timestamp:    uint64(p.Timestamp.Unix()),
+	}
+
+	if p.Post != nil {
+		out.Post = fmt.Sprintf("%s/%s", p.Post.Owner, p.Post.UUID)
+	}
+
+	return &out
+}
+
+// toAPICategories returns all known categories, ones without posts have zero stats.
+func toAPICategories(stats []*storage.CategoryStats) ListCategoriesResponse {
+	byCategory := make(map[community.Category]*storage.CategoryStats, len(stats))
+	for _, v := range stats {
+		byCategory[v.Category] = v
+	}
+
+	out := ListCategoriesResponse{
+		Categories: make([]*CategoryStats, 0, len(community.Category_name)-1),
+	}
+
+	for id, name := range community.Category_name {
+		if community.Category(id) == community.Category_CATEGORY_UNDEFINED {
+			continue
+		}
+
+		c := CategoryStats{
+			ID:    community.Category(id),
+			Name:  name,
+			Stats: []StatsItem{},
+		}
+
+		if s, ok := byCategory[c.ID]; ok {
+			c.PostsCount = s.PostsCount
+			c.ActiveAuthors = s.ActiveAuthors
+			c.LikesCount = s.LikesCount
+
+			for k, v := range s.Stats {
+				c.Stats = append(c.Stats, StatsItem{
+					Date:  k,
+					Value: float64(v),
+				})
+			}
+
+			sort.Slice(c.Stats, func(i, j int) bool {
+				return c.Stats[i].Date < c.Stats[j].Date
+			})
+		}
+
+		out.Categories = append(out.Categories, &c)
+	}
+
+	sort.Slice(out.Categories, func(i, j int) bool {
+		return out.Categories[i].ID < out.Categories[j].ID
+	})
+
+	return out
+}
+
+func toAPILeaderboardItem(i *storage.LeaderboardItem, metric storage.LeaderboardMetric) *LeaderboardItem {
+	value := float64(i.Value)
+	if metric == storage.PDVLeaderboardMetric {
+		value = denominate(i.Value)
+	}
+
+	return &LeaderboardItem{
+		Address: i.Address,
+		Value:   value,
+		Rank:    i.Rank,
+	}
+}
+
+// toUnixTimestamp returns 0 for zero time which means unknown time.
+func toUnixTimestamp(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return uint64(t.Unix())
+}
+
 func toAPIStats(s storage.PostStats) []StatsItem {
 	o := make([]StatsItem, 0, len(s))
 
@@ -670,9 +2094,92 @@ func toAPIProfileStats(s *storage.ProfileStats) ProfileStats {
 	}
 
 	return ProfileStats{
-		PostsCount: s.PostsCount,
-		Stats:      stats,
+		PostsCount:     s.PostsCount,
+		FollowersCount: s.FollowersCount,
+		FollowingCount: s.FollowingCount,
+		LikesCount:     s.LikesCount,
+		DislikesCount:  s.DislikesCount,
+		Stats:          stats,
+	}
+}
+
+func toAPIFollowersStats(s storage.FollowersStats) FollowersStats {
+	out := FollowersStats{
+		Stats: make([]StatsItem, 0, len(s)),
+	}
+
+	for k, v := range s {
+		// follows created before history was collected are counted in the following days
+		if k == "0001-01-01" {
+			continue
+		}
+
+		out.Stats = append(out.Stats, StatsItem{
+			Date:  k,
+			Value: float64(v),
+		})
+	}
+
+	sort.Slice(out.Stats, func(i, j int) bool {
+		return out.Stats[i].Date < out.Stats[j].Date
+	})
+
+	if len(out.Stats) > 0 {
+		out.Followers = int64(out.Stats[len(out.Stats)-1].Value)
+	} else if v, ok := s["0001-01-01"]; ok {
+		out.Followers = v
+	}
+
+	return out
+}
+
+func toAPIBalances(b []*storage.Balance) Balances {
+	// pdv balance is initial when address has no pdv changes
+	pdv := Balance{
+		Denom:   "pdv",
+		Amount:  denominate(storage.PDVDenominator),
+		History: []StatsItem{},
+	}
+
+	out := Balances{
+		Balances: []Balance{pdv},
+	}
+
+	for _, v := range b {
+		if v.Denom == storage.PDVDenom {
+			out.Balances[0].Amount = denominate(storage.PDVDenominator + v.Amount)
+			out.Balances[0].History = toAPIBalanceHistory(v.History, func(v int64) float64 {
+				return denominate(storage.PDVDenominator + v)
+			})
+			continue
+		}
+
+		out.Balances = append(out.Balances, Balance{
+			Denom:  v.Denom,
+			Amount: float64(v.Amount),
+			History: toAPIBalanceHistory(v.History, func(v int64) float64 {
+				return float64(v)
+			}),
+		})
+	}
+
+	return out
+}
+
+func toAPIBalanceHistory(h storage.BalanceHistory, value func(int64) float64) []StatsItem {
+	out := make([]StatsItem, 0, len(h))
+	for k, v := range h {
+		out = append(out, StatsItem{
+			Date:  k,
+			Value: value(v),
+		})
 	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Date < out[j].Date
+	})
+
+	return out
 }
 
 func denominate(v int64) float64 {
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -22,7 +23,17 @@ import (
 func Test_listPosts(t *testing.T) {
 	timestamp := time.Unix(100, 0)
 
-	query := "category=1&sortBy=likesCount&orderBy=asc&limit=100&after=1234/4321&from=1&to=1000&owner=addr&likedBy=1234&followedBy=111&requestedBy=owner&excludeNeutral&excludeNegative"
+	codec := cursorCodec{secret: []byte("secret")}
+	cursor, err := codec.encode(postsCursor{
+		SortBy:  storage.LikesSortType,
+		OrderBy: storage.AscendingOrder,
+		Value:   5,
+		Owner:   "1234",
+		UUID:    "4321",
+	})
+	require.NoError(t, err)
+
+	query := "category=1&sortBy=likesCount&orderBy=asc&limit=100&cursor=" + cursor + "&from=1&to=1000&owner=addr&likedBy=1234&followedBy=111&requestedBy=owner&excludeNeutral&excludeNegative&q=decentr"
 
 	r, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/v1/posts?%s", query), nil)
 	require.NoError(t, err)
@@ -38,8 +49,10 @@ func Test_listPosts(t *testing.T) {
 		assert.Equal(t, "addr", *p.Owner)
 		assert.Equal(t, "1234", *p.LikedBy)
 		assert.Equal(t, "111", *p.FollowedBy)
+		assert.Equal(t, "decentr", *p.Query)
 		assert.EqualValues(t, 100, p.Limit)
-		assert.Equal(t, storage.PostID{
+		assert.Equal(t, storage.PostsCursor{
+			Value: 5,
 			Owner: "1234",
 			UUID:  "4321",
 		}, *p.After)
@@ -83,9 +96,13 @@ func Test_listPosts(t *testing.T) {
 			Stats:      storage.PostStats{"0001-01-01": 1, "1970-01-01": 2},
 		},
 		{
-			Address:    "owner2",
-			PostsCount: 4,
-			Stats:      storage.PostStats{"1970-01-02": 1},
+			Address:        "owner2",
+			PostsCount:     4,
+			FollowersCount: 2,
+			FollowingCount: 3,
+			LikesCount:     5,
+			DislikesCount:  1,
+			Stats:          storage.PostStats{"1970-01-02": 1},
 		},
 	}, nil)
 
@@ -109,7 +126,7 @@ func Test_listPosts(t *testing.T) {
 	}, nil)
 
 	router := chi.NewRouter()
-	srv := server{s: s}
+	srv := server{s: s, cursor: codec}
 	router.Get("/v1/posts", srv.listPosts)
 
 	w := httptest.NewRecorder()
@@ -151,10 +168,18 @@ func Test_listPosts(t *testing.T) {
    "profileStats":{
       "owner":{
 		 "postsCount": 1,
+		 "followersCount": 0,
+		 "followingCount": 0,
+		 "likesCount": 0,
+		 "dislikesCount": 0,
 		 "stats": [{ "date": "1970-01-01", "value": 1.000002 }]
       },
       "owner2":{
 		 "postsCount": 4,
+		 "followersCount": 2,
+		 "followingCount": 3,
+		 "likesCount": 5,
+		 "dislikesCount": 1,
 		 "stats": [{ "date": "1970-01-02", "value": 1.000001 }]
       }
    },
@@ -170,6 +195,500 @@ func Test_listPosts(t *testing.T) {
 	`, w.Body.String())
 }
 
+func Test_listPosts_NextCursor(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "/v1/posts?limit=1&after=owner/uuid", nil)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := mock.NewMockStorage(ctrl)
+
+	codec := cursorCodec{secret: []byte("secret")}
+
+	s.EXPECT().GetPost(gomock.Any(), storage.PostID{Owner: "owner", UUID: "uuid"}).Return(&storage.Post{
+		Owner:     "owner",
+		UUID:      "uuid",
+		CreatedAt: time.Unix(2, 0),
+	}, nil)
+	s.EXPECT().ListPosts(gomock.Any(), &storage.ListPostsParams{
+		SortBy:  storage.CreatedAtSortType,
+		OrderBy: storage.DescendingOrder,
+		Limit:   1,
+		After:   &storage.PostsCursor{Value: 2000000, Owner: "owner", UUID: "uuid"},
+	}).Return([]*storage.Post{
+		{Owner: "owner2", UUID: "uuid2", CreatedAt: time.Unix(1, 0)},
+	}, nil)
+	s.EXPECT().GetProfileStats(gomock.Any(), "owner2").Return([]*storage.ProfileStats{}, nil)
+	s.EXPECT().GetPostStats(gomock.Any(), storage.PostID{Owner: "owner2", UUID: "uuid2"}).Return(nil, nil)
+
+	router := chi.NewRouter()
+	srv := server{s: s, cursor: codec}
+	router.Get("/v1/posts", srv.listPosts)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp ListPostsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	var c postsCursor
+	require.NoError(t, codec.decode(resp.NextCursor, &c))
+	require.Equal(t, postsCursor{
+		SortBy:  storage.CreatedAtSortType,
+		OrderBy: storage.DescendingOrder,
+		Value:   1000000,
+		Owner:   "owner2",
+		UUID:    "uuid2",
+	}, c)
+}
+
+func Test_listPosts_TopPost(t *testing.T) {
+	codec := cursorCodec{secret: []byte("secret")}
+	top := &storage.Post{Owner: topPostID.Owner, UUID: topPostID.UUID, CreatedAt: time.Unix(5, 0)}
+
+	list := func(t *testing.T, s *mock.MockStorage, query string) ListPostsResponse {
+		r, err := http.NewRequest(http.MethodGet, "/v1/posts?"+query, nil)
+		require.NoError(t, err)
+
+		router := chi.NewRouter()
+		srv := server{s: s, cursor: codec}
+		router.Get("/v1/posts", srv.listPosts)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp ListPostsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	t.Run("top post takes place of the last post", func(t *testing.T) {
+		s := mock.NewMockStorage(gomock.NewController(t))
+
+		s.EXPECT().GetPost(gomock.Any(), topPostID).Return(top, nil)
+		s.EXPECT().ListPosts(gomock.Any(), &storage.ListPostsParams{
+			SortBy:  storage.CreatedAtSortType,
+			OrderBy: storage.DescendingOrder,
+			Limit:   1,
+		}).Return([]*storage.Post{
+			{Owner: "owner", UUID: "uuid", CreatedAt: time.Unix(1, 0)},
+		}, nil)
+		s.EXPECT().GetProfileStats(gomock.Any(), topPostID.Owner, "owner").Return([]*storage.ProfileStats{}, nil)
+		s.EXPECT().GetPostStats(gomock.Any(), topPostID, storage.PostID{Owner: "owner", UUID: "uuid"}).Return(nil, nil)
+
+		resp := list(t, s, "limit=2")
+		require.Len(t, resp.Posts, 2)
+		require.Equal(t, topPostID.UUID, resp.Posts[0].UUID)
+
+		var c postsCursor
+		require.NoError(t, codec.decode(resp.NextCursor, &c))
+		require.Equal(t, postsCursor{
+			SortBy:  storage.CreatedAtSortType,
+			OrderBy: storage.DescendingOrder,
+			Value:   1000000,
+			Owner:   "owner",
+			UUID:    "uuid",
+		}, c)
+	})
+
+	t.Run("page with top post only", func(t *testing.T) {
+		s := mock.NewMockStorage(gomock.NewController(t))
+
+		s.EXPECT().GetPost(gomock.Any(), topPostID).Return(top, nil)
+		s.EXPECT().GetProfileStats(gomock.Any(), topPostID.Owner).Return([]*storage.ProfileStats{}, nil)
+		s.EXPECT().GetPostStats(gomock.Any(), topPostID).Return(nil, nil)
+
+		resp := list(t, s, "limit=1")
+		require.Len(t, resp.Posts, 1)
+
+		// the next page starts from the beginning without the top post
+		s.EXPECT().ListPosts(gomock.Any(), &storage.ListPostsParams{
+			SortBy:  storage.CreatedAtSortType,
+			OrderBy: storage.DescendingOrder,
+			Limit:   1,
+		}).Return([]*storage.Post{
+			{Owner: "owner", UUID: "uuid", CreatedAt: time.Unix(1, 0)},
+		}, nil)
+		s.EXPECT().GetProfileStats(gomock.Any(), "owner").Return([]*storage.ProfileStats{}, nil)
+		s.EXPECT().GetPostStats(gomock.Any(), storage.PostID{Owner: "owner", UUID: "uuid"}).Return(nil, nil)
+
+		resp = list(t, s, "limit=1&cursor="+resp.NextCursor)
+		require.Len(t, resp.Posts, 1)
+		require.Equal(t, "uuid", resp.Posts[0].UUID)
+		require.NotEmpty(t, resp.NextCursor)
+	})
+}
+
+func Test_listPosts_InvalidCursor(t *testing.T) {
+	codec := cursorCodec{secret: []byte("secret")}
+	cursor, err := codec.encode(postsCursor{SortBy: storage.LikesSortType, OrderBy: storage.DescendingOrder})
+	require.NoError(t, err)
+
+	for _, query := range []string{
+		"cursor=" + cursor,                       // another sorting
+		"sortBy=likesCount&cursor=" + cursor[1:], // tampered
+	} {
+		r, err := http.NewRequest(http.MethodGet, "/v1/posts?"+query, nil)
+		require.NoError(t, err)
+
+		router := chi.NewRouter()
+		srv := server{cursor: codec}
+		router.Get("/v1/posts", srv.listPosts)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "invalid request: invalid cursor"}`, w.Body.String())
+	}
+}
+
+func Test_listPosts_AfterScoreSort(t *testing.T) {
+	for _, sortBy := range []string{"hot", "rising", "best", "controversial"} {
+		r, err := http.NewRequest(http.MethodGet, "/v1/posts?sortBy="+sortBy+"&after=owner/uuid", nil)
+		require.NoError(t, err)
+
+		router := chi.NewRouter()
+		srv := server{s: mock.NewMockStorage(gomock.NewController(t))}
+		router.Get("/v1/posts", srv.listPosts)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t,
+			fmt.Sprintf(`{"error": "invalid request: after is not supported by %s sorting, use cursor"}`, sortBy),
+			w.Body.String(),
+		)
+	}
+}
+
+func Test_listPosts_RisingCursor(t *testing.T) {
+	codec := cursorCodec{secret: []byte("secret")}
+	now := time.Unix(100, 0).UTC()
+
+	cursor, err := codec.encode(postsCursor{
+		SortBy:  storage.RisingSortType,
+		OrderBy: storage.DescendingOrder,
+		Score:   2,
+		Owner:   "owner",
+		UUID:    "uuid",
+		Now:     now.UnixMicro(),
+	})
+	require.NoError(t, err)
+
+	r, err := http.NewRequest(http.MethodGet, "/v1/posts?sortBy=rising&limit=1&cursor="+cursor, nil)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := mock.NewMockStorage(ctrl)
+
+	// rising scores are calculated at the time of the first page
+	s.EXPECT().ListPosts(gomock.Any(), &storage.ListPostsParams{
+		SortBy:  storage.RisingSortType,
+		OrderBy: storage.DescendingOrder,
+		Limit:   1,
+		After:   &storage.PostsCursor{Score: 2, Owner: "owner", UUID: "uuid"},
+		Now:     now,
+	}).Return([]*storage.Post{
+		{Owner: "owner2", UUID: "uuid2", Score: 1},
+	}, nil)
+	s.EXPECT().GetProfileStats(gomock.Any(), "owner2").Return([]*storage.ProfileStats{}, nil)
+	s.EXPECT().GetPostStats(gomock.Any(), storage.PostID{Owner: "owner2", UUID: "uuid2"}).Return(nil, nil)
+
+	router := chi.NewRouter()
+	srv := server{s: s, cursor: codec}
+	router.Get("/v1/posts", srv.listPosts)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp ListPostsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	var c postsCursor
+	require.NoError(t, codec.decode(resp.NextCursor, &c))
+	require.Equal(t, postsCursor{
+		SortBy:  storage.RisingSortType,
+		OrderBy: storage.DescendingOrder,
+		Score:   1,
+		Owner:   "owner2",
+		UUID:    "uuid2",
+		Now:     now.UnixMicro(),
+	}, c)
+}
+
+func Test_searchPosts(t *testing.T) {
+	codec := cursorCodec{secret: []byte("secret")}
+	cursor, err := codec.encode(searchCursor{Rank: 0.75, Owner: "owner0", UUID: "uuid0"})
+	require.NoError(t, err)
+
+	r, err := http.NewRequest(http.MethodGet, "/v1/posts/search?q=decentr&category=1&limit=1&sortBy=pdv&cursor="+cursor, nil)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := mock.NewMockStorage(ctrl)
+
+	category := community.Category(1)
+	s.EXPECT().SearchPosts(gomock.Any(), &storage.SearchPostsParams{
+		Query:    "decentr",
+		Category: &category,
+		Limit:    1,
+		After:    &storage.SearchCursor{Rank: 0.75, Owner: "owner0", UUID: "uuid0"},
+	}).Return([]*storage.SearchResult{
+		{
+			Post: &storage.Post{
+				UUID:      "uuid",
+				Owner:     "owner",
+				Title:     "title",
+				Category:  1,
+				Text:      "about decentr",
+				CreatedAt: time.Unix(100, 0),
+				Slug:      "slug",
+			},
+			Rank:    0.5,
+			Snippet: "about <b>decentr</b>",
+		},
+	}, nil)
+	s.EXPECT().GetProfileStats(gomock.Any(), "owner").Return([]*storage.ProfileStats{
+		{Address: "owner", PostsCount: 1, Stats: storage.PostStats{}},
+	}, nil)
+	s.EXPECT().GetPostStats(gomock.Any(), storage.PostID{Owner: "owner", UUID: "uuid"}).Return(map[storage.PostID]storage.PostStats{}, nil)
+
+	router := chi.NewRouter()
+	srv := server{s: s, cursor: codec}
+	router.Get("/v1/posts/search", srv.searchPosts)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp SearchPostsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	var c searchCursor
+	require.NoError(t, codec.decode(resp.NextCursor, &c))
+	require.Equal(t, searchCursor{Rank: 0.5, Owner: "owner", UUID: "uuid"}, c)
+
+	resp.NextCursor = ""
+	b, err := json.Marshal(resp)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+   "posts":[
+      {
+         "uuid":"uuid",
+         "owner":"owner",
+         "title":"title",
+         "category":1,
+         "previewImage":"",
+         "text":"about decentr",
+         "likesCount":0,
+         "dislikesCount":0,
+         "pdv":0,
+         "slug":"slug",
+         "likeWeight":0,
+         "createdAt":100
+      }
+   ],
+   "profileStats":{
+      "owner":{
+         "postsCount":1,
+         "followersCount":0,
+         "followingCount":0,
+         "likesCount":0,
+         "dislikesCount":0,
+         "stats":[]
+      }
+   },
+   "stats":{},
+   "snippets":{
+      "owner/uuid":"about <b>decentr</b>"
+   }
+}`, string(b))
+}
+
+func Test_searchPosts_InvalidCursor(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "/v1/posts/search?q=decentr&cursor=3", nil)
+	require.NoError(t, err)
+
+	router := chi.NewRouter()
+	srv := server{cursor: cursorCodec{secret: []byte("secret")}}
+	router.Get("/v1/posts/search", srv.searchPosts)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error": "invalid request: invalid cursor"}`, w.Body.String())
+}
+
+func Test_searchPosts_EmptyQuery(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "/v1/posts/search?q=%20", nil)
+	require.NoError(t, err)
+
+	router := chi.NewRouter()
+	srv := server{}
+	router.Get("/v1/posts/search", srv.searchPosts)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error": "invalid query"}`, w.Body.String())
+}
+
+func Test_listFeed(t *testing.T) {
+	codec := cursorCodec{secret: []byte("secret")}
+	cursor, err := codec.encode(postsCursor{
+		SortBy: storage.HotSortType, OrderBy: storage.DescendingOrder, Score: 2, Owner: "owner", UUID: "uuid",
+	})
+	require.NoError(t, err)
+
+	r, err := http.NewRequest(http.MethodGet, "/v1/feed/address?limit=1&cursor="+cursor, nil)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := mock.NewMockStorage(ctrl)
+
+	s.EXPECT().ListFeed(gomock.Any(), &storage.ListFeedParams{
+		Address: "address",
+		Limit:   1,
+		After:   &storage.PostsCursor{Score: 2, Owner: "owner", UUID: "uuid"},
+	}).Return([]*storage.Post{
+		{Owner: "owner2", UUID: "uuid2", CreatedAt: time.Unix(1, 0), Likes: 1, Score: 1},
+	}, nil)
+	s.EXPECT().GetProfileStats(gomock.Any(), "owner2").Return([]*storage.ProfileStats{}, nil)
+	s.EXPECT().GetPostStats(gomock.Any(), storage.PostID{Owner: "owner2", UUID: "uuid2"}).Return(nil, nil)
+	s.EXPECT().GetLikes(gomock.Any(), "address", storage.PostID{Owner: "owner2", UUID: "uuid2"}).Return(
+		map[storage.PostID]community.LikeWeight{{Owner: "owner2", UUID: "uuid2"}: 1}, nil,
+	)
+
+	router := chi.NewRouter()
+	srv := server{s: s, cursor: codec}
+	router.Get("/v1/feed/{address}", srv.listFeed)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp ListPostsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Posts, 1)
+	require.Equal(t, community.LikeWeight(1), *resp.Posts[0].LikeWeight)
+
+	var c postsCursor
+	require.NoError(t, codec.decode(resp.NextCursor, &c))
+	require.Equal(t, postsCursor{
+		SortBy:  storage.HotSortType,
+		OrderBy: storage.DescendingOrder,
+		Score:   1,
+		Owner:   "owner2",
+		UUID:    "uuid2",
+	}, c)
+}
+
+func Test_listFeed_InvalidCursor(t *testing.T) {
+	codec := cursorCodec{secret: []byte("secret")}
+	cursor, err := codec.encode(postsCursor{SortBy: storage.CreatedAtSortType, OrderBy: storage.DescendingOrder})
+	require.NoError(t, err)
+
+	r, err := http.NewRequest(http.MethodGet, "/v1/feed/address?cursor="+cursor, nil)
+	require.NoError(t, err)
+
+	router := chi.NewRouter()
+	srv := server{cursor: codec}
+	router.Get("/v1/feed/{address}", srv.listFeed)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error": "invalid request: invalid cursor"}`, w.Body.String())
+}
+
+func Test_listRelatedPosts(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "/v1/posts/owner/uuid/related?limit=3", nil)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := mock.NewMockStorage(ctrl)
+
+	id := storage.PostID{Owner: "owner", UUID: "uuid"}
+	category := community.Category(2)
+
+	s.EXPECT().GetPost(gomock.Any(), id).Return(&storage.Post{Owner: "owner", UUID: "uuid", Category: category}, nil)
+	s.EXPECT().ListRelatedPosts(gomock.Any(), id, uint16(3)).Return([]*storage.Post{
+		{Owner: "owner", UUID: "1"},
+	}, nil)
+	s.EXPECT().ListPosts(gomock.Any(), &storage.ListPostsParams{
+		SortBy:   storage.BestSortType,
+		OrderBy:  storage.DescendingOrder,
+		Limit:    5,
+		Category: &category,
+	}).Return([]*storage.Post{
+		{Owner: "owner", UUID: "uuid"},
+		{Owner: "owner", UUID: "1"},
+		{Owner: "owner", UUID: "2"},
+		{Owner: "owner", UUID: "3"},
+		{Owner: "owner", UUID: "4"},
+	}, nil)
+	s.EXPECT().GetProfileStats(gomock.Any(), "owner").Return([]*storage.ProfileStats{}, nil)
+	s.EXPECT().GetPostStats(gomock.Any(),
+		storage.PostID{Owner: "owner", UUID: "1"},
+		storage.PostID{Owner: "owner", UUID: "2"},
+		storage.PostID{Owner: "owner", UUID: "3"},
+	).Return(nil, nil)
+
+	router := chi.NewRouter()
+	srv := server{s: s}
+	router.Get("/v1/posts/{owner}/{uuid}/related", srv.listRelatedPosts)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp ListPostsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Posts, 3)
+	for i, v := range []string{"1", "2", "3"} {
+		require.Equal(t, v, resp.Posts[i].UUID)
+	}
+}
+
+func Test_listRelatedPosts_NotFound(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "/v1/posts/owner/uuid/related", nil)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := mock.NewMockStorage(ctrl)
+
+	s.EXPECT().GetPost(gomock.Any(), storage.PostID{Owner: "owner", UUID: "uuid"}).Return(nil, storage.ErrNotFound)
+
+	router := chi.NewRouter()
+	srv := server{s: s}
+	router.Get("/v1/posts/{owner}/{uuid}/related", srv.listRelatedPosts)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error": "post not found"}`, w.Body.String())
+}
+
 func Test_getPost(t *testing.T) {
 	timestamp := time.Unix(3000, 0)
 
@@ -246,6 +765,10 @@ func Test_getPost(t *testing.T) {
 	},
     "profileStats":{
 		"postsCount":0,
+		"followersCount":0,
+		"followingCount":0,
+		"likesCount":0,
+		"dislikesCount":0,
 		"stats": []
 	},
 	"stats": [
@@ -255,6 +778,86 @@ func Test_getPost(t *testing.T) {
 `, w.Body.String())
 }
 
+func Test_listLikes(t *testing.T) {
+	codec := cursorCodec{secret: []byte("secret")}
+	cursor, err := codec.encode(likesCursor{LikedAt: 3000000, LikedBy: "3"})
+	require.NoError(t, err)
+
+	r, err := http.NewRequest(http.MethodGet, "/v1/posts/owner/uuid/likes?weight=-1&limit=2&cursor="+cursor, nil)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	srv := mock.NewMockStorage(ctrl)
+
+	weight := community.LikeWeight_LIKE_WEIGHT_DOWN
+	srv.EXPECT().ListLikes(gomock.Any(), &storage.ListLikesParams{
+		Post:   storage.PostID{Owner: "owner", UUID: "uuid"},
+		Weight: &weight,
+		Limit:  2,
+		After:  &storage.LikesCursor{LikedAt: time.Unix(3, 0).UTC(), LikedBy: "3"},
+	}).Return([]*storage.Like{
+		{Post: storage.PostID{Owner: "owner", UUID: "uuid"}, LikedBy: "2", Weight: -1, LikedAt: time.Unix(2, 0)},
+		{Post: storage.PostID{Owner: "owner", UUID: "uuid"}, LikedBy: "1", Weight: -1, LikedAt: time.Unix(1, 0)},
+	}, nil)
+
+	router := chi.NewRouter()
+	s := server{s: srv, cursor: codec}
+	router.Get("/v1/posts/{owner}/{uuid}/likes", s.listLikes)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp ListLikesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	var c likesCursor
+	require.NoError(t, codec.decode(resp.NextCursor, &c))
+	require.Equal(t, likesCursor{LikedAt: 1000000, LikedBy: "1"}, c)
+
+	resp.NextCursor = ""
+	b, err := json.Marshal(resp)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+      "likes": [
+          {"likedBy": "2", "weight": -1, "likedAt": 2},
+          {"likedBy": "1", "weight": -1, "likedAt": 1}
+      ]
+    }`, string(b))
+}
+
+func Test_listLikes_InvalidCursor(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "/v1/posts/owner/uuid/likes?cursor=3", nil)
+	require.NoError(t, err)
+
+	router := chi.NewRouter()
+	s := server{cursor: cursorCodec{secret: []byte("secret")}}
+	router.Get("/v1/posts/{owner}/{uuid}/likes", s.listLikes)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error": "invalid request: invalid cursor"}`, w.Body.String())
+}
+
+func Test_listLikes_InvalidWeight(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "/v1/posts/owner/uuid/likes?weight=0", nil)
+	require.NoError(t, err)
+
+	router := chi.NewRouter()
+	s := server{}
+	router.Get("/v1/posts/{owner}/{uuid}/likes", s.listLikes)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error": "invalid weight"}`, w.Body.String())
+}
+
 func Test_getSharePostBySlug(t *testing.T) {
 	timestamp := time.Unix(3000, 0)
 
@@ -307,8 +910,12 @@ func Test_getProfileStats(t *testing.T) {
 
 	srv.EXPECT().GetProfileStats(gomock.Any(), "owner").Return([]*storage.ProfileStats{
 		{
-			PostsCount: 1,
-			Stats:      storage.PostStats{"1970-01-01": 1},
+			PostsCount:     1,
+			FollowersCount: 2,
+			FollowingCount: 3,
+			LikesCount:     4,
+			DislikesCount:  5,
+			Stats:          storage.PostStats{"1970-01-01": 1},
 		},
 	}, nil)
 
@@ -320,7 +927,14 @@ func Test_getProfileStats(t *testing.T) {
 	router.ServeHTTP(w, r)
 
 	assert.Equal(t, http.StatusOK, w.Code)
-	assert.JSONEq(t, `{"postsCount": 1, "stats":[{ "date":"1970-01-01", "value":1.000001 }]}`, w.Body.String())
+	assert.JSONEq(t, `{
+      "postsCount": 1,
+      "followersCount": 2,
+      "followingCount": 3,
+      "likesCount": 4,
+      "dislikesCount": 5,
+      "stats":[{ "date":"1970-01-01", "value":1.000001 }]
+    }`, w.Body.String())
 }
 
 func Test_getDecentrStats(t *testing.T) {
@@ -385,3 +999,541 @@ func Test_getDDVStats(t *testing.T) {
        ]
     }`, w.Body.String())
 }
+
+func Test_listCategories(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "/v1/categories", nil)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := mock.NewMockStorage(ctrl)
+
+	s.EXPECT().GetCategoryStats(gomock.Any()).Return([]*storage.CategoryStats{
+		{
+			Category:      2,
+			PostsCount:    10,
+			ActiveAuthors: 3,
+			LikesCount:    20,
+			Stats:         storage.PostStats{"2022-01-02": 1, "2022-01-01": 2},
+		},
+	}, nil)
+
+	router := chi.NewRouter()
+	srv := server{s: s}
+	router.Get("/v1/categories", srv.listCategories)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp ListCategoriesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	// undefined category isn't listed
+	require.Len(t, resp.Categories, len(community.Category_name)-1)
+
+	for i, v := range resp.Categories {
+		require.Equal(t, community.Category(i+1), v.ID)
+		require.Equal(t, community.Category_name[int32(i+1)], v.Name)
+	}
+
+	require.Equal(t, &CategoryStats{
+		ID:            2,
+		Name:          "CATEGORY_TRAVEL_AND_TOURISM",
+		PostsCount:    10,
+		ActiveAuthors: 3,
+		LikesCount:    20,
+		Stats:         []StatsItem{{Date: "2022-01-01", Value: 2}, {Date: "2022-01-02", Value: 1}},
+	}, resp.Categories[1])
+	require.Equal(t, &CategoryStats{
+		ID:    1,
+		Name:  "CATEGORY_WORLD_NEWS",
+		Stats: []StatsItem{},
+	}, resp.Categories[0])
+}
+
+func Test_getLeaderboard(t *testing.T) {
+	codec := cursorCodec{secret: []byte("secret")}
+	cursor, err := codec.encode(leaderboardCursor{
+		Metric:  storage.PDVLeaderboardMetric,
+		Period:  storage.WeekLeaderboardPeriod,
+		Value:   5000000,
+		Address: "addr0",
+	})
+	require.NoError(t, err)
+
+	r, err := http.NewRequest(http.MethodGet, "/v1/leaderboard?window=week&limit=2&address=addr9&cursor="+cursor, nil)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := mock.NewMockStorage(ctrl)
+
+	s.EXPECT().ListLeaderboard(gomock.Any(), &storage.ListLeaderboardParams{
+		Metric: storage.PDVLeaderboardMetric,
+		Period: storage.WeekLeaderboardPeriod,
+		Limit:  2,
+		After:  &storage.LeaderboardCursor{Value: 5000000, Address: "addr0"},
+	}).Return([]*storage.LeaderboardItem{
+		{Address: "addr1", Value: 3000000, Rank: 2},
+		{Address: "addr2", Value: 1000000, Rank: 3},
+	}, nil)
+	s.EXPECT().GetLeaderboardItem(gomock.Any(), storage.PDVLeaderboardMetric, storage.WeekLeaderboardPeriod, "addr9").
+		Return(&storage.LeaderboardItem{Address: "addr9", Value: 1, Rank: 10}, nil)
+
+	router := chi.NewRouter()
+	srv := server{s: s, cursor: codec}
+	router.Get("/v1/leaderboard", srv.getLeaderboard)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp LeaderboardResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	var c leaderboardCursor
+	require.NoError(t, codec.decode(resp.NextCursor, &c))
+	require.Equal(t, leaderboardCursor{
+		Metric:  storage.PDVLeaderboardMetric,
+		Period:  storage.WeekLeaderboardPeriod,
+		Value:   1000000,
+		Address: "addr2",
+	}, c)
+
+	resp.NextCursor = ""
+	b, err := json.Marshal(resp)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"items": [
+			{"address": "addr1", "value": 3, "rank": 2},
+			{"address": "addr2", "value": 1, "rank": 3}
+		],
+		"addressItem": {"address": "addr9", "value": 0.000001, "rank": 10}
+	}`, string(b))
+}
+
+func Test_getLeaderboard_NotRankedAddress(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "/v1/leaderboard?metric=likes&address=addr", nil)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := mock.NewMockStorage(ctrl)
+
+	s.EXPECT().ListLeaderboard(gomock.Any(), &storage.ListLeaderboardParams{
+		Metric: storage.LikesLeaderboardMetric,
+		Period: storage.AllLeaderboardPeriod,
+		Limit:  20,
+	}).Return([]*storage.LeaderboardItem{
+		{Address: "addr1", Value: 3, Rank: 1},
+	}, nil)
+	s.EXPECT().GetLeaderboardItem(gomock.Any(), storage.LikesLeaderboardMetric, storage.AllLeaderboardPeriod, "addr").
+		Return(nil, storage.ErrNotFound)
+
+	router := chi.NewRouter()
+	srv := server{s: s}
+	router.Get("/v1/leaderboard", srv.getLeaderboard)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"items": [{"address": "addr1", "value": 3, "rank": 1}]}`, w.Body.String())
+}
+
+func Test_getLeaderboard_InvalidRequest(t *testing.T) {
+	codec := cursorCodec{secret: []byte("secret")}
+	cursor, err := codec.encode(leaderboardCursor{Metric: storage.LikesLeaderboardMetric, Period: storage.AllLeaderboardPeriod})
+	require.NoError(t, err)
+
+	for _, q := range []string{
+		"metric=a",
+		"window=year",
+		"limit=0",
+		"cursor=" + cursor[1:], // tampered
+		"cursor=" + cursor,     // another metric
+	} {
+		r, err := http.NewRequest(http.MethodGet, "/v1/leaderboard?"+q, nil)
+		require.NoError(t, err)
+
+		router := chi.NewRouter()
+		s := server{cursor: codec}
+		router.Get("/v1/leaderboard", s.getLeaderboard)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, q)
+	}
+}
+
+func Test_listTokenMovements(t *testing.T) {
+	codec := cursorCodec{secret: []byte("secret")}
+	cursor, err := codec.encode(tokenMovementsCursor{ID: 5})
+	require.NoError(t, err)
+
+	r, err := http.NewRequest(http.MethodGet, "/v1/supply/movements?denom=udec&address=addr&limit=2&cursor="+cursor, nil)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	srv := mock.NewMockStorage(ctrl)
+
+	denom, address, after := "udec", "addr", uint64(5)
+	srv.EXPECT().ListTokenMovements(gomock.Any(), &storage.ListTokenMovementsParams{
+		Denom:   &denom,
+		Address: &address,
+		Limit:   2,
+		After:   &after,
+	}).Return([]*storage.TokenMovement{
+		{ID: 4, Type: storage.BurnTokenMovementType, Address: "addr", Denom: "udec", Amount: 10, Timestamp: time.Unix(200, 0)},
+		{ID: 3, Type: storage.MintTokenMovementType, Address: "addr", Denom: "udec", Amount: 100, Timestamp: time.Unix(100, 0)},
+	}, nil)
+
+	router := chi.NewRouter()
+	s := server{s: srv, cursor: codec}
+	router.Get("/v1/supply/movements", s.listTokenMovements)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp ListTokenMovementsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	var c tokenMovementsCursor
+	require.NoError(t, codec.decode(resp.NextCursor, &c))
+	require.Equal(t, tokenMovementsCursor{ID: 3}, c)
+
+	resp.NextCursor = ""
+	b, err := json.Marshal(resp)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+      "movements": [
+          {"id": 4, "type": "burn", "address": "addr", "denom": "udec", "amount": 10, "timestamp": 200},
+          {"id": 3, "type": "mint", "address": "addr", "denom": "udec", "amount": 100, "timestamp": 100}
+      ]
+    }`, string(b))
+}
+
+func Test_listTokenMovements_InvalidRequest(t *testing.T) {
+	for _, q := range []string{"limit=0", "limit=101", "limit=a", "cursor=3"} {
+		r, err := http.NewRequest(http.MethodGet, "/v1/supply/movements?"+q, nil)
+		require.NoError(t, err)
+
+		router := chi.NewRouter()
+		s := server{cursor: cursorCodec{secret: []byte("secret")}}
+		router.Get("/v1/supply/movements", s.listTokenMovements)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, q)
+	}
+}
+
+func Test_getSupplyStats(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "/v1/supply/stats", nil)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	srv := mock.NewMockStorage(ctrl)
+
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	srv.EXPECT().GetSupplyStats(gomock.Any(), "udec").Return([]*storage.SupplyStatsItem{
+		{Date: date(2022, 1, 1), Minted: 1000, Burned: 0},
+		{Date: date(2022, 1, 2), Minted: 100, Burned: 300},
+	}, nil)
+
+	router := chi.NewRouter()
+	s := server{s: srv}
+	router.Get("/v1/supply/stats", s.getSupplyStats)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+      "denom": "udec",
+      "minted": 1100,
+      "burned": 300,
+      "net": 800,
+      "stats": [
+          {"date": "2022-01-01", "minted": 1000, "burned": 0, "net": 1000, "supplyChange": 1000},
+          {"date": "2022-01-02", "minted": 100, "burned": 300, "net": -200, "supplyChange": 800}
+       ]
+    }`, w.Body.String())
+}
+
+func Test_listFollowers(t *testing.T) {
+	codec := cursorCodec{secret: []byte("secret")}
+	cursor, err := codec.encode(followsCursor{CreatedAt: 3000000, Address: "3"})
+	require.NoError(t, err)
+
+	r, err := http.NewRequest(http.MethodGet, "/v1/profiles/owner/followers?limit=2&requestedBy=me&cursor="+cursor, nil)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	srv := mock.NewMockStorage(ctrl)
+
+	srv.EXPECT().ListFollowers(gomock.Any(), &storage.ListFollowsParams{
+		Address: "owner",
+		Limit:   2,
+		After:   &storage.FollowsCursor{CreatedAt: time.Unix(3, 0).UTC(), Address: "3"},
+	}).Return([]*storage.Follow{
+		{Follower: "2", Followee: "owner", Height: 2, CreatedAt: time.Unix(2, 0)},
+		{Follower: "1", Followee: "owner", CreatedAt: time.Unix(1, 0)},
+	}, nil)
+	srv.EXPECT().GetFollowsCount(gomock.Any(), "owner").Return(&storage.FollowsCount{Followers: 4, Following: 1}, nil)
+	srv.EXPECT().GetFollowed(gomock.Any(), "me", "2", "1").Return(map[string]bool{"1": true}, nil)
+
+	router := chi.NewRouter()
+	s := server{s: srv, cursor: codec}
+	router.Get("/v1/profiles/{address}/followers", s.listFollowers)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp ListFollowsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	var c followsCursor
+	require.NoError(t, codec.decode(resp.NextCursor, &c))
+	require.Equal(t, followsCursor{CreatedAt: 1000000, Address: "1"}, c)
+
+	resp.NextCursor = ""
+	b, err := json.Marshal(resp)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+      "total": 4,
+      "follows": [
+          {"address": "2", "followedAt": 2, "followed": false},
+          {"address": "1", "followedAt": 1, "followed": true}
+      ]
+    }`, string(b))
+}
+
+func Test_listFollowers_InvalidCursor(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "/v1/profiles/owner/followers?cursor=3", nil)
+	require.NoError(t, err)
+
+	router := chi.NewRouter()
+	s := server{s: mock.NewMockStorage(gomock.NewController(t)), cursor: cursorCodec{secret: []byte("secret")}}
+	router.Get("/v1/profiles/{address}/followers", s.listFollowers)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error": "invalid request: invalid cursor"}`, w.Body.String())
+}
+
+func Test_listFollowing(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "/v1/profiles/owner/following", nil)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	srv := mock.NewMockStorage(ctrl)
+
+	srv.EXPECT().ListFollowing(gomock.Any(), &storage.ListFollowsParams{
+		Address: "owner",
+		Limit:   defaultLimit,
+	}).Return([]*storage.Follow{
+		{Follower: "owner", Followee: "1", Height: 1, CreatedAt: time.Unix(1, 0)},
+	}, nil)
+	srv.EXPECT().GetFollowsCount(gomock.Any(), "owner").Return(&storage.FollowsCount{Followers: 4, Following: 1}, nil)
+
+	router := chi.NewRouter()
+	s := server{s: srv}
+	router.Get("/v1/profiles/{address}/following", s.listFollowing)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"total": 1, "follows": [{"address": "1", "followedAt": 1}]}`, w.Body.String())
+}
+
+func Test_getFollowersStats(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "/v1/profiles/owner/followers/stats", nil)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	srv := mock.NewMockStorage(ctrl)
+
+	srv.EXPECT().GetFollowersStats(gomock.Any(), "owner").Return(storage.FollowersStats{
+		"0001-01-01": 1,
+		"2022-01-03": 2,
+		"2022-01-01": 3,
+	}, nil)
+
+	router := chi.NewRouter()
+	s := server{s: srv}
+	router.Get("/v1/profiles/{address}/followers/stats", s.getFollowersStats)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+      "followers": 2,
+      "stats": [{"date": "2022-01-01", "value": 3}, {"date": "2022-01-03", "value": 2}]
+    }`, w.Body.String())
+}
+
+func Test_listFollowSuggestions(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "/v1/profiles/address/suggestions?limit=2", nil)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := mock.NewMockStorage(ctrl)
+
+	s.EXPECT().ListFollowSuggestions(gomock.Any(), "address", uint16(2)).Return([]*storage.FollowSuggestion{
+		{Address: "1", FollowedByFollowees: 2, LikedPosts: 1},
+		{Address: "2", LikedPosts: 1},
+	}, nil)
+
+	router := chi.NewRouter()
+	srv := server{s: s}
+	router.Get("/v1/profiles/{address}/suggestions", srv.listFollowSuggestions)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"suggestions": [
+			{"address": "1", "followedByFollowees": 2, "likedPosts": 1},
+			{"address": "2", "followedByFollowees": 0, "likedPosts": 1}
+		]
+	}`, w.Body.String())
+}
+
+func Test_getBalance(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "/v1/profiles/owner/balance", nil)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	srv := mock.NewMockStorage(ctrl)
+
+	srv.EXPECT().GetBalances(gomock.Any(), "owner").Return([]*storage.Balance{
+		{Denom: "udec", Amount: 100, History: storage.BalanceHistory{"2022-01-02": 100, "2022-01-01": 150}},
+		{Denom: storage.PDVDenom, Amount: 500000, History: storage.BalanceHistory{"2022-01-01": 500000}},
+	}, nil)
+
+	router := chi.NewRouter()
+	s := server{s: srv}
+	router.Get("/v1/profiles/{address}/balance", s.getBalance)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+      "balances": [
+          {"denom": "pdv", "amount": 1.5, "history": [{"date": "2022-01-01", "value": 1.5}]},
+          {"denom": "udec", "amount": 100, "history": [{"date": "2022-01-01", "value": 150}, {"date": "2022-01-02", "value": 100}]}
+      ]
+    }`, w.Body.String())
+}
+
+func Test_getBalance_Initial(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "/v1/profiles/owner/balance", nil)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	srv := mock.NewMockStorage(ctrl)
+
+	srv.EXPECT().GetBalances(gomock.Any(), "owner").Return([]*storage.Balance{}, nil)
+
+	router := chi.NewRouter()
+	s := server{s: srv}
+	router.Get("/v1/profiles/{address}/balance", s.getBalance)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"balances": [{"denom": "pdv", "amount": 1, "history": []}]}`, w.Body.String())
+}
+
+func Test_listPDV(t *testing.T) {
+	codec := cursorCodec{secret: []byte("secret")}
+	cursor, err := codec.encode(pdvCursor{ID: 10})
+	require.NoError(t, err)
+
+	r, err := http.NewRequest(http.MethodGet, "/v1/profiles/owner/pdv?limit=2&cursor="+cursor, nil)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	srv := mock.NewMockStorage(ctrl)
+
+	after := uint64(10)
+	srv.EXPECT().ListPDV(gomock.Any(), &storage.ListPDVParams{
+		Address: "owner",
+		Limit:   2,
+		After:   &after,
+	}).Return([]*storage.PDV{
+		{ID: 9, Address: "owner", Amount: 1, Source: storage.LikePDVSource, Post: &storage.PostID{Owner: "owner", UUID: "uuid"},
+			Counterparty: "liker", Timestamp: time.Unix(200, 0)},
+		{ID: 8, Address: "owner", Amount: 500000, Source: storage.RewardPDVSource, Counterparty: "supervisor", Timestamp: time.Unix(100, 0)},
+	}, nil)
+
+	router := chi.NewRouter()
+	s := server{s: srv, cursor: codec}
+	router.Get("/v1/profiles/{address}/pdv", s.listPDV)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp ListPDVResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	var c pdvCursor
+	require.NoError(t, codec.decode(resp.NextCursor, &c))
+	require.Equal(t, pdvCursor{ID: 8}, c)
+
+	resp.NextCursor = ""
+	b, err := json.Marshal(resp)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+      "pdv": [
+          {"id": 9, "amount": 0.000001, "source": "like", "post": "owner/uuid", "counterparty": "liker", "timestamp": 200},
+          {"id": 8, "amount": 0.5, "source": "reward", "counterparty": "supervisor", "timestamp": 100}
+      ]
+    }`, string(b))
+}
+
+func Test_listPDV_InvalidRequest(t *testing.T) {
+	for _, q := range []string{"limit=0", "limit=a", "cursor=3"} {
+		r, err := http.NewRequest(http.MethodGet, "/v1/profiles/owner/pdv?"+q, nil)
+		require.NoError(t, err)
+
+		router := chi.NewRouter()
+		s := server{cursor: cursorCodec{secret: []byte("secret")}}
+		router.Get("/v1/profiles/{address}/pdv", s.listPDV)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, q)
+	}
+}
@@ -32,11 +32,13 @@ import (
 const maxBodySize = 1024
 
 type server struct {
-	s storage.Storage
+	s      storage.Storage
+	cursor cursorCodec
 }
 
 // SetupRouter setups handlers to chi router.
-func SetupRouter(s storage.Storage, r chi.Router, timeout time.Duration) {
+// cursorSecret is used to sign pagination cursors.
+func SetupRouter(s storage.Storage, r chi.Router, timeout time.Duration, cursorSecret []byte) {
 	r.Use(
 		api.FileServerMiddleware("/docs", "static"),
 		api.LoggerMiddleware,
@@ -49,15 +51,30 @@ func SetupRouter(s storage.Storage, r chi.Router, timeout time.Duration) {
 	)
 
 	srv := server{
-		s: s,
+		s:      s,
+		cursor: cursorCodec{secret: cursorSecret},
 	}
 
 	r.Route("/v1", func(r chi.Router) {
 		r.Get("/posts", srv.listPosts)
+		r.Get("/posts/search", srv.searchPosts)
 		r.Get("/posts/{owner}/{uuid}", srv.getPost)
+		r.Get("/posts/{owner}/{uuid}/likes", srv.listLikes)
+		r.Get("/posts/{owner}/{uuid}/related", srv.listRelatedPosts)
 		r.Get("/posts/{slug}", srv.getSharePostBySlug)
+		r.Get("/feed/{address}", srv.listFeed)
 		r.Get("/profiles/stats", mm.Cached(10*time.Minute, srv.getDecentrStats))
 		r.Get("/ddv/stats", mm.Cached(10*time.Minute, srv.getDDVStats))
+		r.Get("/leaderboard", srv.getLeaderboard)
+		r.Get("/categories", mm.Cached(10*time.Minute, srv.listCategories))
 		r.Get("/profiles/{address}/stats", srv.getProfileStats)
+		r.Get("/profiles/{address}/followers", srv.listFollowers)
+		r.Get("/profiles/{address}/following", srv.listFollowing)
+		r.Get("/profiles/{address}/followers/stats", srv.getFollowersStats)
+		r.Get("/profiles/{address}/suggestions", srv.listFollowSuggestions)
+		r.Get("/profiles/{address}/balance", srv.getBalance)
+		r.Get("/profiles/{address}/pdv", srv.listPDV)
+		r.Get("/supply/movements", srv.listTokenMovements)
+		r.Get("/supply/stats", mm.Cached(10*time.Minute, srv.getSupplyStats))
 	})
 }
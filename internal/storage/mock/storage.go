@@ -79,46 +79,199 @@ func (mr *MockStorageMockRecorder) GetHeight(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeight", reflect.TypeOf((*MockStorage)(nil).GetHeight), ctx)
 }
 
-// RefreshViews mocks base method
-func (m *MockStorage) RefreshViews(ctx context.Context, postView, statsView bool) error {
+// AddMessage mocks base method
+func (m_2 *MockStorage) AddMessage(ctx context.Context, m *storage.Message) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "AddMessage", ctx, m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMessage indicates an expected call of AddMessage
+func (mr *MockStorageMockRecorder) AddMessage(ctx, m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMessage", reflect.TypeOf((*MockStorage)(nil).AddMessage), ctx, m)
+}
+
+// ListMessages mocks base method
+func (m *MockStorage) ListMessages(ctx context.Context, from, to uint64) ([]*storage.Message, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RefreshViews", ctx, postView, statsView)
+	ret := m.ctrl.Call(m, "ListMessages", ctx, from, to)
+	ret0, _ := ret[0].([]*storage.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMessages indicates an expected call of ListMessages
+func (mr *MockStorageMockRecorder) ListMessages(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMessages", reflect.TypeOf((*MockStorage)(nil).ListMessages), ctx, from, to)
+}
+
+// AddJournalRange mocks base method
+func (m *MockStorage) AddJournalRange(ctx context.Context, from, to uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddJournalRange", ctx, from, to)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// RefreshViews indicates an expected call of RefreshViews
-func (mr *MockStorageMockRecorder) RefreshViews(ctx, postView, statsView interface{}) *gomock.Call {
+// AddJournalRange indicates an expected call of AddJournalRange
+func (mr *MockStorageMockRecorder) AddJournalRange(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddJournalRange", reflect.TypeOf((*MockStorage)(nil).AddJournalRange), ctx, from, to)
+}
+
+// ListJournalRanges mocks base method
+func (m *MockStorage) ListJournalRanges(ctx context.Context) ([]*storage.HeightRange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListJournalRanges", ctx)
+	ret0, _ := ret[0].([]*storage.HeightRange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListJournalRanges indicates an expected call of ListJournalRanges
+func (mr *MockStorageMockRecorder) ListJournalRanges(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshViews", reflect.TypeOf((*MockStorage)(nil).RefreshViews), ctx, postView, statsView)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJournalRanges", reflect.TypeOf((*MockStorage)(nil).ListJournalRanges), ctx)
+}
+
+// TruncateDerived mocks base method
+func (m *MockStorage) TruncateDerived(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TruncateDerived", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TruncateDerived indicates an expected call of TruncateDerived
+func (mr *MockStorageMockRecorder) TruncateDerived(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TruncateDerived", reflect.TypeOf((*MockStorage)(nil).TruncateDerived), ctx)
 }
 
 // Follow mocks base method
-func (m *MockStorage) Follow(ctx context.Context, follower, followee string) error {
+func (m *MockStorage) Follow(ctx context.Context, follower, followee string, height uint64, timestamp time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Follow", ctx, follower, followee)
+	ret := m.ctrl.Call(m, "Follow", ctx, follower, followee, height, timestamp)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Follow indicates an expected call of Follow
-func (mr *MockStorageMockRecorder) Follow(ctx, follower, followee interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) Follow(ctx, follower, followee, height, timestamp interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Follow", reflect.TypeOf((*MockStorage)(nil).Follow), ctx, follower, followee)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Follow", reflect.TypeOf((*MockStorage)(nil).Follow), ctx, follower, followee, height, timestamp)
 }
 
 // Unfollow mocks base method
-func (m *MockStorage) Unfollow(ctx context.Context, follower, followee string) error {
+func (m *MockStorage) Unfollow(ctx context.Context, follower, followee string, height uint64, timestamp time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Unfollow", ctx, follower, followee)
+	ret := m.ctrl.Call(m, "Unfollow", ctx, follower, followee, height, timestamp)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Unfollow indicates an expected call of Unfollow
-func (mr *MockStorageMockRecorder) Unfollow(ctx, follower, followee interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) Unfollow(ctx, follower, followee, height, timestamp interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfollow", reflect.TypeOf((*MockStorage)(nil).Unfollow), ctx, follower, followee)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfollow", reflect.TypeOf((*MockStorage)(nil).Unfollow), ctx, follower, followee, height, timestamp)
+}
+
+// GetFollowersStats mocks base method
+func (m *MockStorage) GetFollowersStats(ctx context.Context, address string) (storage.FollowersStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowersStats", ctx, address)
+	ret0, _ := ret[0].(storage.FollowersStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFollowersStats indicates an expected call of GetFollowersStats
+func (mr *MockStorageMockRecorder) GetFollowersStats(ctx, address interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowersStats", reflect.TypeOf((*MockStorage)(nil).GetFollowersStats), ctx, address)
+}
+
+// ListFollowers mocks base method
+func (m *MockStorage) ListFollowers(ctx context.Context, p *storage.ListFollowsParams) ([]*storage.Follow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFollowers", ctx, p)
+	ret0, _ := ret[0].([]*storage.Follow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFollowers indicates an expected call of ListFollowers
+func (mr *MockStorageMockRecorder) ListFollowers(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFollowers", reflect.TypeOf((*MockStorage)(nil).ListFollowers), ctx, p)
+}
+
+// ListFollowing mocks base method
+func (m *MockStorage) ListFollowing(ctx context.Context, p *storage.ListFollowsParams) ([]*storage.Follow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFollowing", ctx, p)
+	ret0, _ := ret[0].([]*storage.Follow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFollowing indicates an expected call of ListFollowing
+func (mr *MockStorageMockRecorder) ListFollowing(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFollowing", reflect.TypeOf((*MockStorage)(nil).ListFollowing), ctx, p)
+}
+
+// GetFollowsCount mocks base method
+func (m *MockStorage) GetFollowsCount(ctx context.Context, address string) (*storage.FollowsCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowsCount", ctx, address)
+	ret0, _ := ret[0].(*storage.FollowsCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFollowsCount indicates an expected call of GetFollowsCount
+func (mr *MockStorageMockRecorder) GetFollowsCount(ctx, address interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowsCount", reflect.TypeOf((*MockStorage)(nil).GetFollowsCount), ctx, address)
+}
+
+// GetFollowed mocks base method
+func (m *MockStorage) GetFollowed(ctx context.Context, follower string, followee ...string) (map[string]bool, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, follower}
+	for _, a := range followee {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetFollowed", varargs...)
+	ret0, _ := ret[0].(map[string]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFollowed indicates an expected call of GetFollowed
+func (mr *MockStorageMockRecorder) GetFollowed(ctx, follower interface{}, followee ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, follower}, followee...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowed", reflect.TypeOf((*MockStorage)(nil).GetFollowed), varargs...)
+}
+
+// ListFollowSuggestions mocks base method
+func (m *MockStorage) ListFollowSuggestions(ctx context.Context, address string, limit uint16) ([]*storage.FollowSuggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFollowSuggestions", ctx, address, limit)
+	ret0, _ := ret[0].([]*storage.FollowSuggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFollowSuggestions indicates an expected call of ListFollowSuggestions
+func (mr *MockStorageMockRecorder) ListFollowSuggestions(ctx, address, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFollowSuggestions", reflect.TypeOf((*MockStorage)(nil).ListFollowSuggestions), ctx, address, limit)
 }
 
 // ListPosts mocks base method
@@ -136,6 +289,51 @@ func (mr *MockStorageMockRecorder) ListPosts(ctx, p interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPosts", reflect.TypeOf((*MockStorage)(nil).ListPosts), ctx, p)
 }
 
+// SearchPosts mocks base method
+func (m *MockStorage) SearchPosts(ctx context.Context, p *storage.SearchPostsParams) ([]*storage.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchPosts", ctx, p)
+	ret0, _ := ret[0].([]*storage.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchPosts indicates an expected call of SearchPosts
+func (mr *MockStorageMockRecorder) SearchPosts(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchPosts", reflect.TypeOf((*MockStorage)(nil).SearchPosts), ctx, p)
+}
+
+// ListFeed mocks base method
+func (m *MockStorage) ListFeed(ctx context.Context, p *storage.ListFeedParams) ([]*storage.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFeed", ctx, p)
+	ret0, _ := ret[0].([]*storage.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFeed indicates an expected call of ListFeed
+func (mr *MockStorageMockRecorder) ListFeed(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFeed", reflect.TypeOf((*MockStorage)(nil).ListFeed), ctx, p)
+}
+
+// ListRelatedPosts mocks base method
+func (m *MockStorage) ListRelatedPosts(ctx context.Context, id storage.PostID, limit uint16) ([]*storage.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRelatedPosts", ctx, id, limit)
+	ret0, _ := ret[0].([]*storage.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRelatedPosts indicates an expected call of ListRelatedPosts
+func (mr *MockStorageMockRecorder) ListRelatedPosts(ctx, id, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRelatedPosts", reflect.TypeOf((*MockStorage)(nil).ListRelatedPosts), ctx, id, limit)
+}
+
 // CreatePost mocks base method
 func (m *MockStorage) CreatePost(ctx context.Context, p *storage.CreatePostParams) error {
 	m.ctrl.T.Helper()
@@ -228,18 +426,107 @@ func (mr *MockStorageMockRecorder) SetLike(ctx, id, weight, timestamp, likeOwner
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLike", reflect.TypeOf((*MockStorage)(nil).SetLike), ctx, id, weight, timestamp, likeOwner)
 }
 
+// ListLikes mocks base method
+func (m *MockStorage) ListLikes(ctx context.Context, p *storage.ListLikesParams) ([]*storage.Like, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLikes", ctx, p)
+	ret0, _ := ret[0].([]*storage.Like)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLikes indicates an expected call of ListLikes
+func (mr *MockStorageMockRecorder) ListLikes(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLikes", reflect.TypeOf((*MockStorage)(nil).ListLikes), ctx, p)
+}
+
 // AddPDV mocks base method
-func (m *MockStorage) AddPDV(ctx context.Context, address string, amount int64, timestamp time.Time) error {
+func (m *MockStorage) AddPDV(ctx context.Context, p *storage.PDV) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddPDV", ctx, address, amount, timestamp)
+	ret := m.ctrl.Call(m, "AddPDV", ctx, p)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddPDV indicates an expected call of AddPDV
-func (mr *MockStorageMockRecorder) AddPDV(ctx, address, amount, timestamp interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) AddPDV(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPDV", reflect.TypeOf((*MockStorage)(nil).AddPDV), ctx, p)
+}
+
+// ListPDV mocks base method
+func (m *MockStorage) ListPDV(ctx context.Context, p *storage.ListPDVParams) ([]*storage.PDV, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPDV", ctx, p)
+	ret0, _ := ret[0].([]*storage.PDV)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPDV indicates an expected call of ListPDV
+func (mr *MockStorageMockRecorder) ListPDV(ctx, p interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPDV", reflect.TypeOf((*MockStorage)(nil).AddPDV), ctx, address, amount, timestamp)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPDV", reflect.TypeOf((*MockStorage)(nil).ListPDV), ctx, p)
+}
+
+// AddTokenMovement mocks base method
+func (m_2 *MockStorage) AddTokenMovement(ctx context.Context, m *storage.TokenMovement) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "AddTokenMovement", ctx, m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTokenMovement indicates an expected call of AddTokenMovement
+func (mr *MockStorageMockRecorder) AddTokenMovement(ctx, m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTokenMovement", reflect.TypeOf((*MockStorage)(nil).AddTokenMovement), ctx, m)
+}
+
+// ListTokenMovements mocks base method
+func (m *MockStorage) ListTokenMovements(ctx context.Context, p *storage.ListTokenMovementsParams) ([]*storage.TokenMovement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTokenMovements", ctx, p)
+	ret0, _ := ret[0].([]*storage.TokenMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTokenMovements indicates an expected call of ListTokenMovements
+func (mr *MockStorageMockRecorder) ListTokenMovements(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTokenMovements", reflect.TypeOf((*MockStorage)(nil).ListTokenMovements), ctx, p)
+}
+
+// GetSupplyStats mocks base method
+func (m *MockStorage) GetSupplyStats(ctx context.Context, denom string) ([]*storage.SupplyStatsItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSupplyStats", ctx, denom)
+	ret0, _ := ret[0].([]*storage.SupplyStatsItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSupplyStats indicates an expected call of GetSupplyStats
+func (mr *MockStorageMockRecorder) GetSupplyStats(ctx, denom interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSupplyStats", reflect.TypeOf((*MockStorage)(nil).GetSupplyStats), ctx, denom)
+}
+
+// GetBalances mocks base method
+func (m *MockStorage) GetBalances(ctx context.Context, address string) ([]*storage.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalances", ctx, address)
+	ret0, _ := ret[0].([]*storage.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalances indicates an expected call of GetBalances
+func (mr *MockStorageMockRecorder) GetBalances(ctx, address interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalances", reflect.TypeOf((*MockStorage)(nil).GetBalances), ctx, address)
 }
 
 // GetProfileStats mocks base method
@@ -282,6 +569,34 @@ func (mr *MockStorageMockRecorder) GetPostStats(ctx interface{}, id ...interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostStats", reflect.TypeOf((*MockStorage)(nil).GetPostStats), varargs...)
 }
 
+// RefreshViews mocks base method
+func (m *MockStorage) RefreshViews(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshViews", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshViews indicates an expected call of RefreshViews
+func (mr *MockStorageMockRecorder) RefreshViews(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshViews", reflect.TypeOf((*MockStorage)(nil).RefreshViews), ctx)
+}
+
+// RefreshRelatedPosts mocks base method
+func (m *MockStorage) RefreshRelatedPosts(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshRelatedPosts", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshRelatedPosts indicates an expected call of RefreshRelatedPosts
+func (mr *MockStorageMockRecorder) RefreshRelatedPosts(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshRelatedPosts", reflect.TypeOf((*MockStorage)(nil).RefreshRelatedPosts), ctx)
+}
+
 // GetDecentrStats mocks base method
 func (m *MockStorage) GetDecentrStats(ctx context.Context) (*storage.DecentrStats, error) {
 	m.ctrl.T.Helper()
@@ -312,6 +627,51 @@ func (mr *MockStorageMockRecorder) GetDDVStats(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDDVStats", reflect.TypeOf((*MockStorage)(nil).GetDDVStats), ctx)
 }
 
+// GetCategoryStats mocks base method
+func (m *MockStorage) GetCategoryStats(ctx context.Context) ([]*storage.CategoryStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategoryStats", ctx)
+	ret0, _ := ret[0].([]*storage.CategoryStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategoryStats indicates an expected call of GetCategoryStats
+func (mr *MockStorageMockRecorder) GetCategoryStats(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryStats", reflect.TypeOf((*MockStorage)(nil).GetCategoryStats), ctx)
+}
+
+// ListLeaderboard mocks base method
+func (m *MockStorage) ListLeaderboard(ctx context.Context, p *storage.ListLeaderboardParams) ([]*storage.LeaderboardItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLeaderboard", ctx, p)
+	ret0, _ := ret[0].([]*storage.LeaderboardItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLeaderboard indicates an expected call of ListLeaderboard
+func (mr *MockStorageMockRecorder) ListLeaderboard(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLeaderboard", reflect.TypeOf((*MockStorage)(nil).ListLeaderboard), ctx, p)
+}
+
+// GetLeaderboardItem mocks base method
+func (m *MockStorage) GetLeaderboardItem(ctx context.Context, metric storage.LeaderboardMetric, period storage.LeaderboardPeriod, address string) (*storage.LeaderboardItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLeaderboardItem", ctx, metric, period, address)
+	ret0, _ := ret[0].(*storage.LeaderboardItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLeaderboardItem indicates an expected call of GetLeaderboardItem
+func (mr *MockStorageMockRecorder) GetLeaderboardItem(ctx, metric, period, address interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeaderboardItem", reflect.TypeOf((*MockStorage)(nil).GetLeaderboardItem), ctx, metric, period, address)
+}
+
 // ResetAccount mocks base method
 func (m *MockStorage) ResetAccount(ctx context.Context, owner string) error {
 	m.ctrl.T.Helper()
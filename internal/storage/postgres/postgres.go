@@ -24,6 +24,15 @@ var errBeginCalledWithinTx = errors.New("can not run WithLockedHeight in tx")
 
 const foreignKeyViolation = "23503"
 
+// risingWindow is a period during which received likes are counted by rising sort.
+const risingWindow = 24 * time.Hour
+
+// feedCategoriesCount is a count of the address' most interacted categories top posts of which are added to feed.
+const feedCategoriesCount = 3
+
+// feedCategoryPostsCount is a count of the hottest posts of each category added to feed.
+const feedCategoryPostsCount = 100
+
 type pg struct {
 	ext sqlx.ExtContext
 }
@@ -40,6 +49,127 @@ type postDTO struct {
 	Dislikes     uint32    `db:"dislikes"`
 	UPDV         int64     `db:"updv"`
 	Slug         string    `db:"slug"`
+	Score        float64   `db:"score"`
+}
+
+type messageDTO struct {
+	Height    uint64    `db:"height"`
+	TxIndex   uint32    `db:"tx_index"`
+	MsgIndex  uint32    `db:"msg_index"`
+	Type      string    `db:"type"`
+	Payload   []byte    `db:"payload"`
+	BlockTime time.Time `db:"block_time"`
+}
+
+type heightRangeDTO struct {
+	From uint64 `db:"from_height"`
+	To   uint64 `db:"to_height"`
+}
+
+type pdvDTO struct {
+	ID           uint64         `db:"id"`
+	Address      string         `db:"address"`
+	UPDV         int64          `db:"updv"`
+	Source       string         `db:"source"`
+	PostOwner    sql.NullString `db:"post_owner"`
+	PostUUID     sql.NullString `db:"post_uuid"`
+	Counterparty string         `db:"counterparty"`
+	Timestamp    time.Time      `db:"timestamp"`
+}
+
+func (p *pdvDTO) toStorage() *storage.PDV {
+	o := storage.PDV{
+		ID:           p.ID,
+		Address:      p.Address,
+		Amount:       p.UPDV,
+		Source:       storage.PDVSource(p.Source),
+		Counterparty: p.Counterparty,
+		Timestamp:    p.Timestamp.UTC(),
+	}
+
+	if p.PostOwner.Valid && p.PostUUID.Valid {
+		o.Post = &storage.PostID{Owner: p.PostOwner.String, UUID: p.PostUUID.String}
+	}
+
+	return &o
+}
+
+type followDTO struct {
+	Follower  string    `db:"follower"`
+	Followee  string    `db:"followee"`
+	Height    uint64    `db:"height"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (f *followDTO) toStorage() *storage.Follow {
+	return &storage.Follow{
+		Follower:  f.Follower,
+		Followee:  f.Followee,
+		Height:    f.Height,
+		CreatedAt: f.CreatedAt.UTC(),
+	}
+}
+
+type likeDTO struct {
+	PostOwner string    `db:"post_owner"`
+	PostUUID  string    `db:"post_uuid"`
+	LikedBy   string    `db:"liked_by"`
+	Weight    int8      `db:"weight"`
+	LikedAt   time.Time `db:"liked_at"`
+}
+
+func (l *likeDTO) toStorage() *storage.Like {
+	return &storage.Like{
+		Post:    storage.PostID{Owner: l.PostOwner, UUID: l.PostUUID},
+		LikedBy: l.LikedBy,
+		Weight:  community.LikeWeight(l.Weight),
+		LikedAt: l.LikedAt.UTC(),
+	}
+}
+
+type tokenMovementDTO struct {
+	ID        uint64    `db:"id"`
+	Type      string    `db:"type"`
+	Address   string    `db:"address"`
+	Denom     string    `db:"denom"`
+	Amount    int64     `db:"amount"`
+	Timestamp time.Time `db:"timestamp"`
+}
+
+func (m *tokenMovementDTO) toStorage() *storage.TokenMovement {
+	return &storage.TokenMovement{
+		ID:        m.ID,
+		Type:      storage.TokenMovementType(m.Type),
+		Address:   m.Address,
+		Denom:     m.Denom,
+		Amount:    m.Amount,
+		Timestamp: m.Timestamp.UTC(),
+	}
+}
+
+type leaderboardItemDTO struct {
+	Address string `db:"address"`
+	Value   int64  `db:"value"`
+	Rank    uint64 `db:"rank"`
+}
+
+func (l *leaderboardItemDTO) toStorage() *storage.LeaderboardItem {
+	return &storage.LeaderboardItem{
+		Address: l.Address,
+		Value:   l.Value,
+		Rank:    l.Rank,
+	}
+}
+
+func (m *messageDTO) toStorage() *storage.Message {
+	return &storage.Message{
+		Height:   m.Height,
+		Time:     m.BlockTime.UTC(),
+		TxIndex:  m.TxIndex,
+		MsgIndex: m.MsgIndex,
+		Type:     m.Type,
+		Payload:  m.Payload,
+	}
 }
 
 func (p *postDTO) toStorage() *storage.Post {
@@ -55,6 +185,7 @@ func (p *postDTO) toStorage() *storage.Post {
 		UPDV:         p.UPDV,
 		CreatedAt:    p.CreatedAt,
 		Slug:         p.Slug,
+		Score:        p.Score,
 	}
 
 	// return post consistent with blockchain
@@ -112,21 +243,83 @@ func (s pg) SetHeight(ctx context.Context, height uint64) error {
 	return nil
 }
 
-func (s pg) RefreshViews(ctx context.Context, postView, statsView bool) error {
-	if postView {
-		if _, err := s.ext.ExecContext(ctx, `REFRESH MATERIALIZED VIEW calculated_post`); err != nil {
-			return fmt.Errorf("failed to refresh calculated_post view: failed to exec: %w", err)
-		}
+func (s pg) AddMessage(ctx context.Context, m *storage.Message) error {
+	if _, err := s.ext.ExecContext(ctx, `
+			INSERT INTO message(height, tx_index, msg_index, type, payload, block_time)
+			VALUES($1, $2, $3, $4, $5, $6)
+		`, m.Height, m.TxIndex, m.MsgIndex, m.Type, string(m.Payload), m.Time.UTC(),
+	); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
 
-		if _, err := s.ext.ExecContext(ctx, `REFRESH MATERIALIZED VIEW stats`); err != nil {
-			return fmt.Errorf("failed to refresh stats view: failed to exec: %w", err)
-		}
+	return nil
+}
+
+func (s pg) ListMessages(ctx context.Context, from, to uint64) ([]*storage.Message, error) {
+	var res []*messageDTO
+	if err := sqlx.SelectContext(ctx, s.ext, &res, `
+			SELECT height, tx_index, msg_index, type, payload, block_time
+			FROM message
+			WHERE height BETWEEN $1 AND $2
+			ORDER BY height, tx_index, msg_index
+		`, from, to,
+	); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
 	}
 
-	if statsView {
-		if _, err := s.ext.ExecContext(ctx, `REFRESH MATERIALIZED VIEW pdv_stats`); err != nil {
-			return fmt.Errorf("failed to refresh pdv_stats view: failed to exec: %w", err)
-		}
+	out := make([]*storage.Message, len(res))
+	for i, v := range res {
+		out[i] = v.toStorage()
+	}
+
+	return out, nil
+}
+
+func (s pg) AddJournalRange(ctx context.Context, from, to uint64) error {
+	// overlapping and adjacent ranges are merged into the new one
+	if _, err := s.ext.ExecContext(ctx, `
+		WITH merged AS (
+			DELETE FROM journal_range
+			WHERE to_height >= $1::BIGINT - 1 AND from_height <= $2::BIGINT + 1
+			RETURNING from_height, to_height
+		)
+		INSERT INTO journal_range(from_height, to_height)
+		SELECT LEAST($1::BIGINT, MIN(from_height)), GREATEST($2::BIGINT, MAX(to_height)) FROM merged
+	`, from, to); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+
+	return nil
+}
+
+func (s pg) ListJournalRanges(ctx context.Context) ([]*storage.HeightRange, error) {
+	var res []*heightRangeDTO
+	if err := sqlx.SelectContext(ctx, s.ext, &res, `
+		SELECT from_height, to_height FROM journal_range ORDER BY from_height
+	`); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	out := make([]*storage.HeightRange, len(res))
+	for i, v := range res {
+		out[i] = &storage.HeightRange{From: v.From, To: v.To}
+	}
+
+	return out, nil
+}
+
+func (s pg) TruncateDerived(ctx context.Context) error {
+	if _, ok := s.ext.(*sqlx.Tx); !ok {
+		return errors.New("TruncateDerived can be run only in tx mode") //nolint:goerr113
+	}
+
+	if _, err := s.ext.ExecContext(ctx, `
+		TRUNCATE
+			post, "like", like_event, follow, follow_event, timeline, updv,
+			post_stats_daily, pdv_stats_daily, token_movement, balance, balance_daily
+		RESTART IDENTITY
+	`); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
 	}
 
 	return nil
@@ -141,20 +334,50 @@ func (s pg) GetProfileStats(ctx context.Context, addr ...string) ([]*storage.Pro
 
 	query, args, err := sqlx.In(`
 			WITH
+			r AS (
+			    SELECT UNNEST(ARRAY[?]::TEXT[]) AS address
+			),
 			pc AS (
-			    SELECT owner AS address, COUNT(*) as posts_count
+			    SELECT
+			        owner AS address, COUNT(*) as posts_count,
+			        SUM(likes) AS likes_count, SUM(dislikes) AS dislikes_count
 			    FROM post
+			    INNER JOIN r ON post.owner = r.address
 			    WHERE deleted_at IS NULL
-			    GROUP BY address
+			    GROUP BY owner
 			),
-			r AS (
-			    SELECT UNNEST(ARRAY[?]::TEXT[]) AS address
+			fc AS (
+			    SELECT followee AS address, COUNT(*) AS followers_count
+			    FROM follow
+			    INNER JOIN r ON follow.followee = r.address
+			    GROUP BY followee
+			),
+			fgc AS (
+			    SELECT follower AS address, COUNT(*) AS following_count
+			    FROM follow
+			    INNER JOIN r ON follow.follower = r.address
+			    GROUP BY follower
+			),
+			pdv AS (
+			    SELECT address, date, SUM(updv) OVER (PARTITION BY address ORDER BY date) AS updv
+			    FROM pdv_stats_daily
+			    INNER JOIN r USING (address)
+			),
+			ps AS (
+			    SELECT address, json_object_agg(date, updv) AS stats
+			    FROM pdv
+			    GROUP BY address
 			)
 			SELECT
-			    r.address, COALESCE(posts_count, 0) AS posts_count, stats
+			    r.address, COALESCE(posts_count, 0) AS posts_count,
+			    COALESCE(followers_count, 0) AS followers_count, COALESCE(following_count, 0) AS following_count,
+			    COALESCE(likes_count, 0) AS likes_count, COALESCE(dislikes_count, 0) AS dislikes_count,
+			    stats
 			FROM r
 		         LEFT JOIN pc USING (address)
-		         LEFT JOIN pdv_stats USING (address)
+		         LEFT JOIN fc USING (address)
+		         LEFT JOIN fgc USING (address)
+		         LEFT JOIN ps USING (address)
 			ORDER BY address
 		`, addr)
 
@@ -163,9 +386,13 @@ func (s pg) GetProfileStats(ctx context.Context, addr ...string) ([]*storage.Pro
 	}
 
 	var p []*struct {
-		Address    string `db:"address"`
-		PostsCount uint16 `db:"posts_count"`
-		Stats      []byte `db:"stats"`
+		Address        string `db:"address"`
+		PostsCount     uint16 `db:"posts_count"`
+		FollowersCount uint32 `db:"followers_count"`
+		FollowingCount uint32 `db:"following_count"`
+		LikesCount     uint32 `db:"likes_count"`
+		DislikesCount  uint32 `db:"dislikes_count"`
+		Stats          []byte `db:"stats"`
 	}
 
 	if err := sqlx.SelectContext(ctx, s.ext, &p, s.ext.Rebind(query), args...); err != nil {
@@ -175,9 +402,13 @@ func (s pg) GetProfileStats(ctx context.Context, addr ...string) ([]*storage.Pro
 	out := make([]*storage.ProfileStats, len(p))
 	for i, v := range p {
 		out[i] = &storage.ProfileStats{
-			Address:    v.Address,
-			PostsCount: v.PostsCount,
-			Stats:      storage.PostStats{},
+			Address:        v.Address,
+			PostsCount:     v.PostsCount,
+			FollowersCount: v.FollowersCount,
+			FollowingCount: v.FollowingCount,
+			LikesCount:     v.LikesCount,
+			DislikesCount:  v.DislikesCount,
+			Stats:          storage.PostStats{},
 		}
 
 		if v.Stats != nil {
@@ -201,10 +432,18 @@ func (s pg) CreatePost(ctx context.Context, p *storage.CreatePostParams) error {
 		CreatedAt:    p.CreatedAt.UTC(),
 	}
 
+	// the post is fanned out to followers' timelines
 	if _, err := sqlx.NamedExecContext(ctx, s.ext,
 		`
-			INSERT INTO post(owner, uuid, title, category, preview_image, text, created_at)
-			VALUES(:owner, :uuid, :title, :category, :preview_image, :text, :created_at)
+			WITH ins AS (
+				INSERT INTO post(owner, uuid, title, category, preview_image, text, created_at)
+				VALUES(:owner, :uuid, :title, :category, :preview_image, :text, :created_at)
+				RETURNING owner, uuid, created_at
+			)
+			INSERT INTO timeline(follower, post_owner, post_uuid, created_at)
+			SELECT follow.follower, ins.owner, ins.uuid, ins.created_at
+			FROM ins
+			INNER JOIN follow ON follow.followee = ins.owner
 		`, post,
 	); err != nil {
 		return fmt.Errorf("failed to exec: %w", err)
@@ -267,6 +506,13 @@ func (s pg) DeletePost(ctx context.Context, id storage.PostID, timestamp time.Ti
 		return storage.ErrNotFound
 	}
 
+	if _, err := s.ext.ExecContext(ctx,
+		`DELETE FROM timeline WHERE post_owner=$1 AND post_uuid=$2`,
+		id.Owner, id.UUID,
+	); err != nil {
+		return fmt.Errorf("failed to delete from timeline: %w", err)
+	}
+
 	return nil
 }
 
@@ -309,11 +555,20 @@ func (s pg) GetLikes(ctx context.Context, likedBy string, id ...storage.PostID)
 
 func (s pg) SetLike(ctx context.Context, id storage.PostID, weight community.LikeWeight,
 	timestamp time.Time, likeOwner string) error {
+	// like keeps the last weight, like_event keeps the history
 	if _, err := s.ext.ExecContext(ctx, `
-			INSERT INTO "like"(post_owner, post_uuid, liked_by, weight, liked_at)
-				VALUES($1, $2, $3, $4, $5)
-			ON CONFLICT(post_owner, post_uuid, liked_by) DO UPDATE SET
-				weight=excluded.weight, liked_at=excluded.liked_at`,
+			WITH
+			prev AS (
+				SELECT weight FROM "like" WHERE post_owner = $1 AND post_uuid = $2 AND liked_by = $3
+			),
+			upsert AS (
+				INSERT INTO "like"(post_owner, post_uuid, liked_by, weight, liked_at)
+					VALUES($1, $2, $3, $4, $5)
+				ON CONFLICT(post_owner, post_uuid, liked_by) DO UPDATE SET
+					weight=excluded.weight, liked_at=excluded.liked_at
+			)
+			INSERT INTO like_event(post_owner, post_uuid, liked_by, weight, delta, timestamp)
+			SELECT $1, $2, $3, $4, $4 - COALESCE((SELECT weight FROM prev), 0), $5`,
 		id.Owner, id.UUID, likeOwner, weight, timestamp.UTC(),
 	); err != nil {
 		if err, ok := err.(*pq.Error); ok && err.Code == foreignKeyViolation {
@@ -326,11 +581,62 @@ func (s pg) SetLike(ctx context.Context, id storage.PostID, weight community.Lik
 	return nil
 }
 
-func (s pg) Follow(ctx context.Context, follower, followee string) error {
+func (s pg) ListLikes(ctx context.Context, p *storage.ListLikesParams) ([]*storage.Like, error) {
+	var b strings.Builder
+	args := []interface{}{p.Post.Owner, p.Post.UUID}
+
+	// removed likes have zero weight
+	b.WriteString(`
+		SELECT post_owner, post_uuid, liked_by, weight, liked_at FROM "like"
+		WHERE post_owner = ? AND post_uuid = ? AND weight != 0
+	`)
+
+	if p.Weight != nil {
+		b.WriteString(` AND weight = ?`)
+		args = append(args, *p.Weight)
+	}
+
+	if p.After != nil {
+		b.WriteString(` AND (liked_at, liked_by) < (?, ?)`)
+		args = append(args, p.After.LikedAt.UTC(), p.After.LikedBy)
+	}
+
+	b.WriteString(`
+		ORDER BY liked_at DESC, liked_by DESC LIMIT ?
+	`)
+	args = append(args, p.Limit)
+
+	var res []*likeDTO
+	if err := sqlx.SelectContext(ctx, s.ext, &res, s.ext.Rebind(b.String()), args...); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	out := make([]*storage.Like, len(res))
+	for i, v := range res {
+		out[i] = v.toStorage()
+	}
+
+	return out, nil
+}
+
+func (s pg) Follow(ctx context.Context, follower, followee string, height uint64, timestamp time.Time) error {
+	// the event is saved and followee's posts are backfilled to timeline only when the follow is created
 	if _, err := s.ext.ExecContext(ctx,
 		`
-			INSERT INTO follow(follower, followee) VALUES($1, $2) ON CONFLICT DO NOTHING
-		`, follower, followee,
+			WITH ins AS (
+				INSERT INTO follow(follower, followee, height, created_at) VALUES($1, $2, $3, $4)
+				ON CONFLICT DO NOTHING
+				RETURNING follower, followee
+			),
+			event AS (
+				INSERT INTO follow_event(follower, followee, followed, height, timestamp)
+				SELECT follower, followee, TRUE, $3, $4 FROM ins
+			)
+			INSERT INTO timeline(follower, post_owner, post_uuid, created_at)
+			SELECT ins.follower, post.owner, post.uuid, post.created_at
+			FROM ins
+			INNER JOIN post ON post.owner = ins.followee AND post.deleted_at IS NULL
+		`, follower, followee, height, timestamp.UTC(),
 	); err != nil {
 		return fmt.Errorf("failed to exec: %w", err)
 	}
@@ -338,11 +644,21 @@ func (s pg) Follow(ctx context.Context, follower, followee string) error {
 	return nil
 }
 
-func (s pg) Unfollow(ctx context.Context, follower, followee string) error {
+func (s pg) Unfollow(ctx context.Context, follower, followee string, height uint64, timestamp time.Time) error {
+	// the event is saved and followee's posts are removed from timeline only when the follow is deleted
 	if _, err := s.ext.ExecContext(ctx,
 		`
-			DELETE FROM follow WHERE follower=$1 AND followee=$2
-		`, follower, followee,
+			WITH del AS (
+				DELETE FROM follow WHERE follower=$1 AND followee=$2
+				RETURNING follower, followee
+			),
+			event AS (
+				INSERT INTO follow_event(follower, followee, followed, height, timestamp)
+				SELECT follower, followee, FALSE, $3, $4 FROM del
+			)
+			DELETE FROM timeline USING del
+			WHERE timeline.follower = del.follower AND timeline.post_owner = del.followee
+		`, follower, followee, height, timestamp.UTC(),
 	); err != nil {
 		return fmt.Errorf("failed to exec: %w", err)
 	}
@@ -350,19 +666,201 @@ func (s pg) Unfollow(ctx context.Context, follower, followee string) error {
 	return nil
 }
 
+func (s pg) GetFollowersStats(ctx context.Context, address string) (storage.FollowersStats, error) {
+	var stats []byte
+
+	if err := sqlx.GetContext(ctx, s.ext, &stats, `
+		WITH
+		d AS (
+			SELECT timestamp::DATE AS date, SUM(CASE WHEN followed THEN 1 ELSE -1 END) AS diff
+			FROM follow_event
+			WHERE followee = $1
+			GROUP BY date
+		),
+		r AS (
+			SELECT date, SUM(diff) OVER (ORDER BY date) AS followers
+			FROM d
+		)
+		SELECT json_object_agg(date, followers) FROM r
+	`, address); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	out := storage.FollowersStats{}
+	if stats != nil {
+		if err := json.Unmarshal(stats, &out); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal stats: %w", err)
+		}
+	}
+
+	return out, nil
+}
+
+func (s pg) ListFollowers(ctx context.Context, p *storage.ListFollowsParams) ([]*storage.Follow, error) {
+	return s.listFollows(ctx, "followee", "follower", p)
+}
+
+func (s pg) ListFollowing(ctx context.Context, p *storage.ListFollowsParams) ([]*storage.Follow, error) {
+	return s.listFollows(ctx, "follower", "followee", p)
+}
+
+// listFollows returns follows where by column is equal to address ordered by creation time, the latest first.
+// The listed column is used as a tie-breaker.
+func (s pg) listFollows(ctx context.Context, by, listed string, p *storage.ListFollowsParams) ([]*storage.Follow, error) {
+	var b strings.Builder
+	args := []interface{}{p.Address}
+
+	// nolint: gosec
+	b.WriteString(fmt.Sprintf(`
+		SELECT follower, followee, height, created_at FROM follow
+		WHERE %s = ?
+	`, by))
+
+	if p.After != nil {
+		// nolint: gosec
+		b.WriteString(fmt.Sprintf(`
+			AND (created_at, %s) < (?, ?)
+		`, listed))
+		args = append(args, p.After.CreatedAt.UTC(), p.After.Address)
+	}
+
+	// nolint: gosec
+	b.WriteString(fmt.Sprintf(`
+		ORDER BY created_at DESC, %s DESC LIMIT ?
+	`, listed))
+	args = append(args, p.Limit)
+
+	var res []*followDTO
+	if err := sqlx.SelectContext(ctx, s.ext, &res, s.ext.Rebind(b.String()), args...); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	out := make([]*storage.Follow, len(res))
+	for i, v := range res {
+		out[i] = v.toStorage()
+	}
+
+	return out, nil
+}
+
+func (s pg) GetFollowsCount(ctx context.Context, address string) (*storage.FollowsCount, error) {
+	var res struct {
+		Followers uint32 `db:"followers"`
+		Following uint32 `db:"following"`
+	}
+
+	if err := sqlx.GetContext(ctx, s.ext, &res, `
+		SELECT
+			(SELECT COUNT(*) FROM follow WHERE followee = $1) AS followers,
+			(SELECT COUNT(*) FROM follow WHERE follower = $1) AS following
+	`, address); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	return &storage.FollowsCount{
+		Followers: res.Followers,
+		Following: res.Following,
+	}, nil
+}
+
+func (s pg) GetFollowed(ctx context.Context, follower string, followee ...string) (map[string]bool, error) {
+	if len(followee) == 0 {
+		return map[string]bool{}, nil
+	}
+
+	var res []string
+	if err := sqlx.SelectContext(ctx, s.ext, &res, `
+		SELECT followee FROM follow WHERE follower = $1 AND followee = ANY($2)
+	`, follower, pq.StringArray(followee)); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	out := make(map[string]bool, len(res))
+	for _, v := range res {
+		out[v] = true
+	}
+
+	return out, nil
+}
+
+// ListFollowSuggestions returns addresses followed by the address' followees and authors of posts liked by the address.
+// Already followed addresses and the address itself are excluded. Suggestions with more reasons are returned first.
+func (s pg) ListFollowSuggestions(ctx context.Context, address string, limit uint16) ([]*storage.FollowSuggestion, error) {
+	var res []*struct {
+		Address             string `db:"address"`
+		FollowedByFollowees uint32 `db:"followed_by_followees"`
+		LikedPosts          uint32 `db:"liked_posts"`
+	}
+
+	if err := sqlx.SelectContext(ctx, s.ext, &res, `
+		WITH
+		followee AS (
+			SELECT followee FROM follow WHERE follower = $1
+		),
+		fof AS (
+			SELECT followee AS address, COUNT(*) AS followed_by_followees
+			FROM follow
+			WHERE follower IN (SELECT followee FROM followee)
+			GROUP BY followee
+		),
+		liked AS (
+			SELECT post_owner AS address, COUNT(*) AS liked_posts
+			FROM "like"
+			WHERE liked_by = $1 AND weight > 0
+			GROUP BY post_owner
+		)
+		SELECT
+			address,
+			COALESCE(followed_by_followees, 0) AS followed_by_followees,
+			COALESCE(liked_posts, 0) AS liked_posts
+		FROM fof
+		FULL JOIN liked USING (address)
+		WHERE address != $1 AND address NOT IN (SELECT followee FROM followee)
+		ORDER BY COALESCE(followed_by_followees, 0) + COALESCE(liked_posts, 0) DESC, address
+		LIMIT $2
+	`, address, limit); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	out := make([]*storage.FollowSuggestion, len(res))
+	for i, v := range res {
+		out[i] = &storage.FollowSuggestion{
+			Address:             v.Address,
+			FollowedByFollowees: v.FollowedByFollowees,
+			LikedPosts:          v.LikedPosts,
+		}
+	}
+
+	return out, nil
+}
+
 func (s pg) ListPosts(ctx context.Context, p *storage.ListPostsParams) ([]*storage.Post, error) {
 	var b strings.Builder
 	var args []interface{}
 
+	sortKey := listPostsSortKey(p)
+
 	b.WriteString(`
 		SELECT
-			owner, uuid, title, category, preview_image, text, created_at, likes, dislikes, updv, slug
+			owner, uuid, title, category, preview_image, text, calculated_post.created_at,
+			likes, dislikes, updv, slug
+	`)
+
+	if isScoreSortType(p.SortBy) {
+		b.WriteString(fmt.Sprintf(`, %s AS score`, sortKey[0])) // nolint: gosec
+	}
+
+	b.WriteString(`
 		FROM calculated_post
 	`)
 
+	// timeline has created_at column too, so calculated_post columns are qualified
 	if p.FollowedBy != nil {
 		b.WriteString(`
-			INNER JOIN follow ON calculated_post.owner = follow.followee AND follow.follower = ?
+			INNER JOIN timeline
+			ON
+				calculated_post.owner = timeline.post_owner AND calculated_post.uuid = timeline.post_uuid AND
+				timeline.follower = ?
 		`)
 		args = append(args, *p.FollowedBy)
 	}
@@ -377,6 +875,28 @@ func (s pg) ListPosts(ctx context.Context, p *storage.ListPostsParams) ([]*stora
 		args = append(args, *p.LikedBy)
 	}
 
+	if p.SortBy == storage.RisingSortType {
+		now := p.Now.UTC()
+		if now.IsZero() {
+			now = time.Now().UTC()
+		}
+
+		b.WriteString(`
+			INNER JOIN (
+				SELECT
+					post_owner, post_uuid,
+					SUM(delta) / POWER(DATE_PART('epoch', ?::TIMESTAMP - post.created_at) / 3600 + 2, 1.5) AS score
+				FROM like_event
+				INNER JOIN post ON like_event.post_owner = post.owner AND like_event.post_uuid = post.uuid
+				WHERE like_event.timestamp > ?
+				GROUP BY post_owner, post_uuid, post.created_at
+				HAVING SUM(delta) > 0
+			) rising
+			ON calculated_post.owner = rising.post_owner AND calculated_post.uuid = rising.post_uuid
+		`)
+		args = append(args, now, now.Add(-risingWindow))
+	}
+
 	if wheres, whereArgs := whereClausesFromListPostsParams(p); len(wheres) > 0 {
 		toJoin := make([]string, len(wheres))
 		for i, v := range wheres {
@@ -387,8 +907,8 @@ func (s pg) ListPosts(ctx context.Context, p *storage.ListPostsParams) ([]*stora
 	}
 
 	b.WriteString(fmt.Sprintf(`
-		ORDER BY %s %s, owner %s, uuid %s LIMIT ?
-	`, p.SortBy, p.OrderBy, p.OrderBy, p.OrderBy))
+		ORDER BY %s %s, %s %s, %s %s LIMIT ?
+	`, sortKey[0], p.OrderBy, sortKey[1], p.OrderBy, sortKey[2], p.OrderBy))
 	args = append(args, p.Limit)
 
 	query := s.ext.Rebind(b.String())
@@ -406,6 +926,165 @@ func (s pg) ListPosts(ctx context.Context, p *storage.ListPostsParams) ([]*stora
 	return out, nil
 }
 
+func (s pg) SearchPosts(ctx context.Context, p *storage.SearchPostsParams) ([]*storage.SearchResult, error) {
+	var b strings.Builder
+	args := []interface{}{p.Query}
+
+	b.WriteString(`
+		WITH
+		q AS (
+			SELECT websearch_to_tsquery('simple', ?) AS q
+		),
+		r AS (
+			SELECT
+				owner, uuid, title, category, preview_image, text, created_at, likes, dislikes, updv, slug,
+				ts_rank(search, q.q) AS rank
+			FROM calculated_post, q
+			WHERE search @@ q.q
+	`)
+
+	if p.Category != nil {
+		b.WriteString(` AND category = ?`)
+		args = append(args, *p.Category)
+	}
+
+	b.WriteString(`
+		)
+		SELECT
+			r.*,
+			ts_headline('simple', text, q.q, 'StartSel=<b>, StopSel=</b>, MinWords=15, MaxWords=35') AS snippet
+		FROM r, q
+	`)
+
+	// rank is REAL, so the cursor value is cast back to not miss it by float8 comparison
+	if p.After != nil {
+		b.WriteString(` WHERE (rank, owner, uuid) < (?::REAL, ?, ?)`)
+		args = append(args, p.After.Rank, p.After.Owner, p.After.UUID)
+	}
+
+	b.WriteString(`
+		ORDER BY rank DESC, owner DESC, uuid DESC LIMIT ?
+	`)
+	args = append(args, p.Limit)
+
+	var res []*struct {
+		postDTO
+		Rank    float64 `db:"rank"`
+		Snippet string  `db:"snippet"`
+	}
+
+	if err := sqlx.SelectContext(ctx, s.ext, &res, s.ext.Rebind(b.String()), args...); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	out := make([]*storage.SearchResult, len(res))
+	for i, v := range res {
+		out[i] = &storage.SearchResult{
+			Post:    v.toStorage(),
+			Rank:    v.Rank,
+			Snippet: v.Snippet,
+		}
+	}
+
+	return out, nil
+}
+
+func (s pg) ListFeed(ctx context.Context, p *storage.ListFeedParams) ([]*storage.Post, error) {
+	var b strings.Builder
+	args := []interface{}{
+		p.Address, p.Address, p.Address, feedCategoriesCount, feedCategoryPostsCount, p.Address, p.Address,
+	}
+
+	// candidates are deduplicated by union
+	b.WriteString(`
+		WITH
+		followee AS (
+			SELECT followee FROM follow WHERE follower = ?
+		),
+		top_category AS (
+			SELECT category FROM (
+				SELECT post.category FROM "like"
+				INNER JOIN post ON "like".post_owner = post.owner AND "like".post_uuid = post.uuid
+				WHERE "like".liked_by = ? AND "like".weight > 0
+				UNION ALL
+				SELECT category FROM post WHERE owner = ? AND deleted_at IS NULL
+			) c
+			GROUP BY category
+			ORDER BY COUNT(*) DESC, category
+			LIMIT ?
+		),
+		category_candidate AS (
+			SELECT c.owner, c.uuid FROM top_category
+			CROSS JOIN LATERAL (
+				SELECT owner, uuid FROM post
+				WHERE category = top_category.category AND deleted_at IS NULL AND likes > dislikes
+				ORDER BY hot DESC
+				LIMIT ?
+			) c
+		),
+		candidate AS (
+			SELECT post_owner AS owner, post_uuid AS uuid FROM timeline WHERE follower = ?
+			UNION
+			SELECT post_owner, post_uuid FROM "like" WHERE liked_by IN (SELECT followee FROM followee) AND weight > 0
+			UNION
+			SELECT owner, uuid FROM category_candidate
+		)
+		SELECT
+			owner, uuid, title, category, preview_image, text, created_at, likes, dislikes, updv, slug,
+			hot AS score
+		FROM calculated_post
+		INNER JOIN candidate USING (owner, uuid)
+		WHERE owner != ?
+	`)
+
+	if p.After != nil {
+		b.WriteString(` AND (hot, owner, uuid) < (?, ?, ?)`)
+		args = append(args, p.After.Score, p.After.Owner, p.After.UUID)
+	}
+
+	b.WriteString(`
+		ORDER BY hot DESC, owner DESC, uuid DESC LIMIT ?
+	`)
+	args = append(args, p.Limit)
+
+	var res []*postDTO
+	if err := sqlx.SelectContext(ctx, s.ext, &res, s.ext.Rebind(b.String()), args...); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	out := make([]*storage.Post, len(res))
+	for i, v := range res {
+		out[i] = v.toStorage()
+	}
+
+	return out, nil
+}
+
+// ListRelatedPosts returns posts liked by the same addresses that liked the post, the most common first.
+// Relations are read from related_post view, so it's actual on the last RefreshRelatedPosts call.
+func (s pg) ListRelatedPosts(ctx context.Context, id storage.PostID, limit uint16) ([]*storage.Post, error) {
+	var res []*postDTO
+	if err := sqlx.SelectContext(ctx, s.ext, &res, `
+		SELECT
+			owner, uuid, title, category, preview_image, text, created_at, likes, dislikes, updv, slug
+		FROM related_post
+		INNER JOIN calculated_post
+		ON related_post.related_owner = calculated_post.owner AND related_post.related_uuid = calculated_post.uuid
+		WHERE related_post.post_owner = $1 AND related_post.post_uuid = $2
+		ORDER BY rank
+		LIMIT $3
+	`, id.Owner, id.UUID, limit); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	out := make([]*storage.Post, len(res))
+	for i, v := range res {
+		out[i] = v.toStorage()
+	}
+
+	return out, nil
+}
+
 func (s pg) GetPostStats(ctx context.Context, id ...storage.PostID) (map[storage.PostID]storage.PostStats, error) {
 	if len(id) == 0 {
 		return map[storage.PostID]storage.PostStats{}, nil
@@ -426,9 +1105,15 @@ func (s pg) GetPostStats(ctx context.Context, id ...storage.PostID) (map[storage
 	var res []*statsDTO
 
 	if err := sqlx.SelectContext(ctx, s.ext, &res, `
-		WITH clause AS ( SELECT UNNEST($1::TEXT[]) AS owner, UNNEST($2::TEXT[]) AS uuid )
-		SELECT owner, uuid, stats FROM stats
-			INNER JOIN clause USING(owner, uuid)
+		WITH
+		clause AS ( SELECT UNNEST($1::TEXT[]) AS owner, UNNEST($2::TEXT[]) AS uuid ),
+		r AS (
+			SELECT owner, uuid, date, SUM(updv) OVER (PARTITION BY owner, uuid ORDER BY date) AS updv
+			FROM post_stats_daily
+				INNER JOIN clause USING(owner, uuid)
+		)
+		SELECT owner, uuid, json_object_agg(date, updv) AS stats FROM r
+		GROUP BY owner, uuid
 	`, pq.StringArray(owners), pq.StringArray(uuids)); err != nil {
 		return nil, fmt.Errorf("failed to select: %w", err)
 	}
@@ -447,10 +1132,16 @@ func (s pg) GetPostStats(ctx context.Context, id ...storage.PostID) (map[storage
 	return out, nil
 }
 
-func (s pg) AddPDV(ctx context.Context, address string, updv int64, timestamp time.Time) error {
+func (s pg) AddPDV(ctx context.Context, p *storage.PDV) error {
+	var postOwner, postUUID *string
+	if p.Post != nil {
+		postOwner, postUUID = &p.Post.Owner, &p.Post.UUID
+	}
+
 	_, err := s.ext.ExecContext(ctx, `
-		INSERT INTO updv(address, updv, timestamp) VALUES($1, $2, $3)
-	`, address, updv, timestamp)
+		INSERT INTO updv(address, updv, source, post_owner, post_uuid, counterparty, timestamp)
+		VALUES($1, $2, $3, $4, $5, $6, $7)
+	`, p.Address, p.Amount, p.Source, postOwner, postUUID, p.Counterparty, p.Timestamp)
 
 	if err != nil {
 		return fmt.Errorf("failed to insert: %w", err)
@@ -459,6 +1150,183 @@ func (s pg) AddPDV(ctx context.Context, address string, updv int64, timestamp ti
 	return nil
 }
 
+func (s pg) ListPDV(ctx context.Context, p *storage.ListPDVParams) ([]*storage.PDV, error) {
+	var b strings.Builder
+	args := []interface{}{p.Address}
+
+	b.WriteString(`
+		SELECT id, address, updv, source, post_owner, post_uuid, counterparty, timestamp
+		FROM updv
+		WHERE address = ?
+	`)
+
+	if p.After != nil {
+		b.WriteString(` AND id < ?`)
+		args = append(args, *p.After)
+	}
+
+	b.WriteString(`
+		ORDER BY id DESC LIMIT ?
+	`)
+	args = append(args, p.Limit)
+
+	var res []*pdvDTO
+	if err := sqlx.SelectContext(ctx, s.ext, &res, s.ext.Rebind(b.String()), args...); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	out := make([]*storage.PDV, len(res))
+	for i, v := range res {
+		out[i] = v.toStorage()
+	}
+
+	return out, nil
+}
+
+func (s pg) AddTokenMovement(ctx context.Context, m *storage.TokenMovement) error {
+	if _, err := s.ext.ExecContext(ctx, `
+		INSERT INTO token_movement(type, address, denom, amount, timestamp) VALUES($1, $2, $3, $4, $5)
+	`, m.Type, m.Address, m.Denom, m.Amount, m.Timestamp.UTC()); err != nil {
+		return fmt.Errorf("failed to insert: %w", err)
+	}
+
+	return nil
+}
+
+func (s pg) ListTokenMovements(ctx context.Context, p *storage.ListTokenMovementsParams) ([]*storage.TokenMovement, error) {
+	var b strings.Builder
+	var wheres []string
+	var args []interface{}
+
+	b.WriteString(`
+		SELECT id, type, address, denom, amount, timestamp FROM token_movement
+	`)
+
+	if p.Denom != nil {
+		wheres = append(wheres, "denom = ?")
+		args = append(args, *p.Denom)
+	}
+
+	if p.Address != nil {
+		wheres = append(wheres, "address = ?")
+		args = append(args, *p.Address)
+	}
+
+	if p.After != nil {
+		wheres = append(wheres, "id < ?")
+		args = append(args, *p.After)
+	}
+
+	if len(wheres) > 0 {
+		b.WriteString(` WHERE ` + strings.Join(wheres, " AND ")) // nolint: gosec
+	}
+
+	b.WriteString(`
+		ORDER BY id DESC LIMIT ?
+	`)
+	args = append(args, p.Limit)
+
+	var res []*tokenMovementDTO
+	if err := sqlx.SelectContext(ctx, s.ext, &res, s.ext.Rebind(b.String()), args...); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	out := make([]*storage.TokenMovement, len(res))
+	for i, v := range res {
+		out[i] = v.toStorage()
+	}
+
+	return out, nil
+}
+
+func (s pg) GetSupplyStats(ctx context.Context, denom string) ([]*storage.SupplyStatsItem, error) {
+	var stats []*storage.SupplyStatsItem
+	if err := sqlx.SelectContext(ctx, s.ext, &stats, `
+		SELECT
+			timestamp::DATE AS date,
+			COALESCE(SUM(amount) FILTER (WHERE type = 'mint'), 0) AS minted,
+			COALESCE(SUM(amount) FILTER (WHERE type = 'burn'), 0) AS burned
+		FROM token_movement
+		WHERE denom = $1
+		GROUP BY date
+		ORDER BY date
+	`, denom); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	return stats, nil
+}
+
+func (s pg) GetBalances(ctx context.Context, address string) ([]*storage.Balance, error) {
+	var res []*struct {
+		Denom   string `db:"denom"`
+		Amount  int64  `db:"amount"`
+		History []byte `db:"history"`
+	}
+
+	if err := sqlx.SelectContext(ctx, s.ext, &res, `
+		WITH
+		h AS (
+			SELECT denom, date, SUM(amount) OVER (PARTITION BY denom ORDER BY date) AS amount
+			FROM balance_daily
+			WHERE address = $1
+		),
+		hs AS (
+			SELECT denom, json_object_agg(date, amount) AS history
+			FROM h
+			GROUP BY denom
+		)
+		SELECT denom, amount, history
+		FROM balance
+			LEFT JOIN hs USING (denom)
+		WHERE address = $1
+		ORDER BY denom
+	`, address); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	out := make([]*storage.Balance, len(res))
+	for i, v := range res {
+		out[i] = &storage.Balance{
+			Denom:   v.Denom,
+			Amount:  v.Amount,
+			History: storage.BalanceHistory{},
+		}
+
+		if v.History != nil {
+			if err := json.Unmarshal(v.History, &out[i].History); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal history: %w", err)
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// RefreshViews refreshes stats, leaderboard and categories views concurrently,
+// so readers are not blocked by the refresh.
+func (s pg) RefreshViews(ctx context.Context) error {
+	for _, v := range []string{
+		"decentr_stats", "ddv_stats", "leaderboard", "category_stats", "category_stats_daily",
+	} {
+		if _, err := s.ext.ExecContext(ctx, fmt.Sprintf(`REFRESH MATERIALIZED VIEW CONCURRENTLY %s`, v)); err != nil {
+			return fmt.Errorf("failed to refresh %s view: failed to exec: %w", v, err)
+		}
+	}
+
+	return nil
+}
+
+// RefreshRelatedPosts refreshes related posts view concurrently.
+// It's refreshed apart from other views, because it self-joins likes and is much more expensive.
+func (s pg) RefreshRelatedPosts(ctx context.Context) error {
+	if _, err := s.ext.ExecContext(ctx, `REFRESH MATERIALIZED VIEW CONCURRENTLY related_post`); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+
+	return nil
+}
+
 func (s pg) GetDecentrStats(ctx context.Context) (*storage.DecentrStats, error) {
 	var statsDTO struct {
 		DDV int64   `db:"ddv"`
@@ -466,11 +1334,7 @@ func (s pg) GetDecentrStats(ctx context.Context) (*storage.DecentrStats, error)
 	}
 
 	if err := sqlx.GetContext(ctx, s.ext, &statsDTO, `
-		WITH pdv AS (
-			SELECT address, SUM(updv) AS earned_pdv, SUM(updv) + $1 AS current_pdv from updv
-			group by address
-		)
-		SELECT SUM(earned_pdv) AS ddv, AVG(current_pdv) AS adv FROM pdv;
+		SELECT ddv, avg_earned_pdv + $1 AS adv FROM decentr_stats
 	`, storage.PDVDenominator); err != nil {
 		return nil, fmt.Errorf("failed to select: %w", err)
 	}
@@ -484,15 +1348,104 @@ func (s pg) GetDecentrStats(ctx context.Context) (*storage.DecentrStats, error)
 func (s pg) GetDDVStats(ctx context.Context) ([]*storage.DDVStatsItem, error) {
 	var stats []*storage.DDVStatsItem
 	err := sqlx.SelectContext(ctx, s.ext, &stats, `
-				SELECT timestamp::DATE as date, SUM(updv) as value
-				FROM updv
-				WHERE timestamp > NOW() -'90 day'::INTERVAL
-				GROUP BY date
+				SELECT date, value
+				FROM ddv_stats
+				WHERE date > (NOW() - '90 day'::INTERVAL)::DATE
 				ORDER BY date DESC
 	`)
 	return stats, err
 }
 
+func (s pg) GetCategoryStats(ctx context.Context) ([]*storage.CategoryStats, error) {
+	var res []*struct {
+		Category      uint8           `db:"category"`
+		PostsCount    uint32          `db:"posts_count"`
+		ActiveAuthors uint32          `db:"active_authors"`
+		Likes         uint32          `db:"likes"`
+		Stats         json.RawMessage `db:"stats"`
+	}
+
+	if err := sqlx.SelectContext(ctx, s.ext, &res, `
+		WITH daily AS (
+			SELECT category, json_object_agg(date, posts_count) AS stats
+			FROM category_stats_daily
+			WHERE date > (NOW() - '90 day'::INTERVAL)::DATE
+			GROUP BY category
+		)
+		SELECT category, posts_count, active_authors, likes, COALESCE(daily.stats, '{}') AS stats
+		FROM category_stats
+		LEFT JOIN daily USING (category)
+		ORDER BY category
+	`); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	out := make([]*storage.CategoryStats, len(res))
+	for i, v := range res {
+		out[i] = &storage.CategoryStats{
+			Category:      community.Category(v.Category),
+			PostsCount:    v.PostsCount,
+			ActiveAuthors: v.ActiveAuthors,
+			LikesCount:    v.Likes,
+		}
+
+		if err := json.Unmarshal(v.Stats, &out[i].Stats); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal stats: %w", err)
+		}
+	}
+
+	return out, nil
+}
+
+func (s pg) ListLeaderboard(ctx context.Context, p *storage.ListLeaderboardParams) ([]*storage.LeaderboardItem, error) {
+	var b strings.Builder
+	args := []interface{}{p.Metric, p.Period}
+
+	b.WriteString(`
+		SELECT address, value, rank FROM leaderboard WHERE metric = ? AND period = ?
+	`)
+
+	// values are ordered the same way as ranks, but they are kept by refresh
+	if p.After != nil {
+		b.WriteString(` AND (value, address) < (?, ?)`)
+		args = append(args, p.After.Value, p.After.Address)
+	}
+
+	b.WriteString(`
+		ORDER BY value DESC, address DESC LIMIT ?
+	`)
+	args = append(args, p.Limit)
+
+	var res []*leaderboardItemDTO
+	if err := sqlx.SelectContext(ctx, s.ext, &res, s.ext.Rebind(b.String()), args...); err != nil {
+		return nil, fmt.Errorf("failed to select: %w", err)
+	}
+
+	out := make([]*storage.LeaderboardItem, len(res))
+	for i, v := range res {
+		out[i] = v.toStorage()
+	}
+
+	return out, nil
+}
+
+func (s pg) GetLeaderboardItem(
+	ctx context.Context, metric storage.LeaderboardMetric, period storage.LeaderboardPeriod, address string,
+) (*storage.LeaderboardItem, error) {
+	var res leaderboardItemDTO
+	if err := sqlx.GetContext(ctx, s.ext, &res, `
+		SELECT address, value, rank FROM leaderboard WHERE metric = $1 AND period = $2 AND address = $3
+	`, metric, period, address); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrNotFound
+		}
+
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+
+	return res.toStorage(), nil
+}
+
 func (s pg) ResetAccount(ctx context.Context, owner string) error {
 	if _, ok := s.ext.(*sqlx.Tx); !ok {
 		return errors.New("ResetAccount can be run only in tx mode") //nolint:goerr113
@@ -504,25 +1457,68 @@ func (s pg) ResetAccount(ctx context.Context, owner string) error {
 		return fmt.Errorf("failed to delete follows: %w", err)
 	}
 
+	if _, err := s.ext.ExecContext(ctx, `
+		DELETE FROM follow_event WHERE followee = $1 OR follower = $1
+	`, owner); err != nil {
+		return fmt.Errorf("failed to delete follow events: %w", err)
+	}
+
+	if _, err := s.ext.ExecContext(ctx, `
+		DELETE FROM timeline WHERE follower = $1 OR post_owner = $1
+	`, owner); err != nil {
+		return fmt.Errorf("failed to delete timeline: %w", err)
+	}
+
 	if _, err := s.ext.ExecContext(ctx, `
 		DELETE FROM "like" WHERE liked_by = $1 OR post_owner = $1
 	`, owner); err != nil {
 		return fmt.Errorf("failed to delete likes: %w", err)
 	}
 
+	if _, err := s.ext.ExecContext(ctx, `
+		DELETE FROM like_event WHERE liked_by = $1 OR post_owner = $1
+	`, owner); err != nil {
+		return fmt.Errorf("failed to delete like events: %w", err)
+	}
+
 	if _, err := s.ext.ExecContext(ctx, `
 		DELETE FROM post WHERE owner = $1
 	`, owner); err != nil {
 		return fmt.Errorf("failed to delete user from posts: %w", err)
 	}
 
+	if _, err := s.ext.ExecContext(ctx, `
+		DELETE FROM post_stats_daily WHERE owner = $1
+	`, owner); err != nil {
+		return fmt.Errorf("failed to delete posts stats: %w", err)
+	}
+
 	if _, err := s.ext.ExecContext(ctx, `
 		DELETE FROM updv WHERE address = $1
 	`, owner); err != nil {
 		return fmt.Errorf("failed to delete updv: %w", err)
 	}
 
-	return s.RefreshViews(ctx, true, true)
+	if _, err := s.ext.ExecContext(ctx, `
+		DELETE FROM pdv_stats_daily WHERE address = $1
+	`, owner); err != nil {
+		return fmt.Errorf("failed to delete pdv stats: %w", err)
+	}
+
+	// pdv balance is reset to the initial one, other balances are kept
+	if _, err := s.ext.ExecContext(ctx, `
+		DELETE FROM balance_daily WHERE address = $1 AND denom = $2
+	`, owner, storage.PDVDenom); err != nil {
+		return fmt.Errorf("failed to delete balance history: %w", err)
+	}
+
+	if _, err := s.ext.ExecContext(ctx, `
+		DELETE FROM balance WHERE address = $1 AND denom = $2
+	`, owner, storage.PDVDenom); err != nil {
+		return fmt.Errorf("failed to delete balance: %w", err)
+	}
+
+	return nil
 }
 
 // New creates new instance of pg.
@@ -546,6 +1542,28 @@ func stringsUnique(s []string) []string {
 	return out
 }
 
+// listPostsSortKey returns qualified fields posts list is sorted by: sort field, owner and uuid.
+// Posts of followees sorted by creation time are ordered by timeline index.
+func listPostsSortKey(p *storage.ListPostsParams) [3]string {
+	switch {
+	case p.SortBy == storage.RisingSortType:
+		return [3]string{"rising.score", "owner", "uuid"}
+	case p.FollowedBy != nil && p.SortBy == storage.CreatedAtSortType:
+		return [3]string{"timeline.created_at", "timeline.post_owner", "timeline.post_uuid"}
+	default:
+		return [3]string{fmt.Sprintf("calculated_post.%s", p.SortBy), "owner", "uuid"}
+	}
+}
+
+func isScoreSortType(s storage.SortType) bool {
+	switch s {
+	case storage.HotSortType, storage.RisingSortType, storage.BestSortType, storage.ControversialSortType:
+		return true
+	default:
+		return false
+	}
+}
+
 func whereClausesFromListPostsParams(p *storage.ListPostsParams) ([]string, []interface{}) {
 	var (
 		where []string
@@ -562,13 +1580,18 @@ func whereClausesFromListPostsParams(p *storage.ListPostsParams) ([]string, []in
 		args = append(args, *p.Owner)
 	}
 
+	if p.Query != nil {
+		where = append(where, `search @@ websearch_to_tsquery('simple', ?)`)
+		args = append(args, *p.Query)
+	}
+
 	if p.From != nil {
-		where = append(where, `created_at > ?`)
+		where = append(where, `calculated_post.created_at > ?`)
 		args = append(args, time.Unix(int64(*p.From), 0).UTC())
 	}
 
 	if p.To != nil {
-		where = append(where, `created_at < ?`)
+		where = append(where, `calculated_post.created_at < ?`)
 		args = append(args, time.Unix(int64(*p.To), 0).UTC())
 	}
 
@@ -586,15 +1609,19 @@ func whereClausesFromListPostsParams(p *storage.ListPostsParams) ([]string, []in
 			comp = ">"
 		}
 
-		// nolint: gosec
-		where = append(where, fmt.Sprintf(`
-			%s %s (SELECT %s FROM calculated_post WHERE owner = ? AND uuid = ? FETCH FIRST ROW ONLY) OR (
-				%s = (SELECT %s FROM calculated_post WHERE owner = ? AND uuid = ? FETCH FIRST ROW ONLY) AND
-				CONCAT(owner,uuid) %s CONCAT(?::TEXT,?::TEXT)
-			)
-		`, p.SortBy, comp, p.SortBy, p.SortBy, p.SortBy, comp))
+		var value interface{} = p.After.Value
+		switch {
+		case p.SortBy == storage.CreatedAtSortType:
+			value = time.UnixMicro(p.After.Value).UTC()
+		case isScoreSortType(p.SortBy):
+			value = p.After.Score
+		}
 
-		args = append(args, p.After.Owner, p.After.UUID, p.After.Owner, p.After.UUID, p.After.Owner, p.After.UUID)
+		// row values comparison matches the order and uses sort field index
+		// nolint: gosec
+		key := listPostsSortKey(p)
+		where = append(where, fmt.Sprintf(`(%s, %s, %s) %s (?, ?, ?)`, key[0], key[1], key[2], comp))
+		args = append(args, value, p.After.Owner, p.After.UUID)
 	}
 
 	return where, args
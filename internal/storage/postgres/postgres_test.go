@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"testing"
 	"time"
 
@@ -123,12 +124,36 @@ func cleanup(t *testing.T) {
 	require.NoError(t, err)
 	_, err = db.ExecContext(ctx, `DELETE FROM "like"`)
 	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `DELETE FROM like_event`)
+	require.NoError(t, err)
 	_, err = db.ExecContext(ctx, `DELETE FROM post`)
 	require.NoError(t, err)
 	_, err = db.ExecContext(ctx, `DELETE FROM updv`)
 	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `DELETE FROM message`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `DELETE FROM journal_range`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `DELETE FROM post_slug`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `DELETE FROM post_stats_daily`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `DELETE FROM pdv_stats_daily`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `DELETE FROM token_movement`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `DELETE FROM balance`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `DELETE FROM balance_daily`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `DELETE FROM timeline`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `DELETE FROM follow`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `DELETE FROM follow_event`)
+	require.NoError(t, err)
 
-	require.NoError(t, s.RefreshViews(ctx, true, true))
+	require.NoError(t, s.RefreshViews(ctx))
 }
 
 func TestPg_GetHeight(t *testing.T) {
@@ -162,6 +187,124 @@ func TestPg_InTx(t *testing.T) {
 	require.EqualValues(t, 1, h)
 }
 
+func TestPg_AddMessage(t *testing.T) {
+	defer cleanup(t)
+
+	timestamp := time.Unix(100, 0).UTC()
+
+	require.NoError(t, s.AddMessage(ctx, &storage.Message{
+		Height:   1,
+		Time:     timestamp,
+		TxIndex:  2,
+		MsgIndex: 3,
+		Type:     "/community.MsgFollow",
+		Payload:  []byte(`{"owner":"1","whom":"2"}`),
+	}))
+
+	var m struct {
+		Height    uint64    `db:"height"`
+		TxIndex   uint32    `db:"tx_index"`
+		MsgIndex  uint32    `db:"msg_index"`
+		Type      string    `db:"type"`
+		Payload   string    `db:"payload"`
+		BlockTime time.Time `db:"block_time"`
+	}
+
+	require.NoError(t, sqlx.NewDb(db, "postgres").GetContext(ctx, &m, `SELECT * FROM message`))
+	require.EqualValues(t, 1, m.Height)
+	require.EqualValues(t, 2, m.TxIndex)
+	require.EqualValues(t, 3, m.MsgIndex)
+	require.Equal(t, "/community.MsgFollow", m.Type)
+	require.JSONEq(t, `{"owner":"1","whom":"2"}`, m.Payload)
+	require.Equal(t, timestamp.Unix(), m.BlockTime.Unix())
+
+	// journal is append-only
+	require.Error(t, s.AddMessage(ctx, &storage.Message{Height: 1, TxIndex: 2, MsgIndex: 3, Payload: []byte(`{}`)}))
+}
+
+func TestPg_ListMessages(t *testing.T) {
+	defer cleanup(t)
+
+	timestamp := time.Unix(100, 0).UTC()
+
+	for _, v := range []*storage.Message{
+		{Height: 2, TxIndex: 0, MsgIndex: 0, Type: "/community.MsgFollow", Payload: []byte(`{"owner":"3"}`)},
+		{Height: 1, TxIndex: 1, MsgIndex: 0, Type: "/community.MsgFollow", Payload: []byte(`{"owner":"2"}`)},
+		{Height: 1, TxIndex: 0, MsgIndex: 1, Type: "/community.MsgFollow", Payload: []byte(`{"owner":"1"}`)},
+		{Height: 1, TxIndex: 0, MsgIndex: 0, Type: "/community.MsgFollow", Payload: []byte(`{"owner":"0"}`)},
+		{Height: 3, TxIndex: 0, MsgIndex: 0, Type: "/community.MsgFollow", Payload: []byte(`{"owner":"4"}`)},
+	} {
+		v.Time = timestamp
+		require.NoError(t, s.AddMessage(ctx, v))
+	}
+
+	m, err := s.ListMessages(ctx, 1, 2)
+	require.NoError(t, err)
+	require.Len(t, m, 4)
+
+	for i, v := range m {
+		require.JSONEq(t, fmt.Sprintf(`{"owner":"%d"}`, i), string(v.Payload))
+		require.Equal(t, "/community.MsgFollow", v.Type)
+		require.Equal(t, timestamp, v.Time)
+	}
+	require.EqualValues(t, 1, m[2].TxIndex)
+	require.EqualValues(t, 2, m[3].Height)
+
+	m, err = s.ListMessages(ctx, 4, 10)
+	require.NoError(t, err)
+	require.Empty(t, m)
+}
+
+func TestPg_JournalRanges(t *testing.T) {
+	defer cleanup(t)
+
+	for _, r := range [][2]uint64{{5, 7}, {0, 0}, {10, 12}, {1, 3}, {8, 9}, {11, 20}} {
+		require.NoError(t, s.AddJournalRange(ctx, r[0], r[1]))
+	}
+
+	ranges, err := s.ListJournalRanges(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []*storage.HeightRange{{From: 0, To: 3}, {From: 5, To: 20}}, ranges)
+
+	require.NoError(t, s.AddJournalRange(ctx, 4, 4))
+
+	ranges, err = s.ListJournalRanges(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []*storage.HeightRange{{From: 0, To: 20}}, ranges)
+}
+
+func TestPg_TruncateDerived(t *testing.T) {
+	defer cleanup(t)
+
+	require.Error(t, s.TruncateDerived(ctx))
+
+	p := storage.PostID{Owner: "owner", UUID: "uuid"}
+	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: p.UUID, Owner: p.Owner, CreatedAt: time.Now()}))
+	require.NoError(t, s.SetLike(ctx, p, community.LikeWeight_LIKE_WEIGHT_UP, time.Now(), "liker"))
+	require.NoError(t, s.Follow(ctx, "liker", "owner", 1, time.Now()))
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "owner", Amount: 1, Source: storage.RewardPDVSource, Timestamp: time.Now()}))
+
+	post, err := s.GetPost(ctx, p)
+	require.NoError(t, err)
+
+	require.NoError(t, s.InTx(ctx, func(s storage.Storage) error {
+		return s.TruncateDerived(ctx)
+	}))
+
+	for _, table := range []string{"post", `"like"`, "follow", "updv"} {
+		var count int
+		require.NoError(t, db.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+table).Scan(&count))
+		require.Zero(t, count, table)
+	}
+
+	// recreated post gets the same slug
+	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: p.UUID, Owner: p.Owner, CreatedAt: time.Now()}))
+
+	recreated, err := s.GetPost(ctx, p)
+	require.NoError(t, err)
+	require.Equal(t, post.Slug, recreated.Slug)
+}
+
 func TestPg_GetProfileStats(t *testing.T) {
 	defer cleanup(t)
 
@@ -175,25 +318,33 @@ func TestPg_GetProfileStats(t *testing.T) {
 	}))
 	require.NoError(t, s.DeletePost(ctx, storage.PostID{"address", "124"}, time.Now(), "address_2"))
 
+	require.NoError(t, s.SetLike(ctx, storage.PostID{"address", "123"}, 1, time.Now(), "address_1"))
+	require.NoError(t, s.SetLike(ctx, storage.PostID{"address", "123"}, -1, time.Now(), "address_2"))
+	require.NoError(t, s.Follow(ctx, "address_1", "address", 1, time.Now()))
+	require.NoError(t, s.Follow(ctx, "address_2", "address", 1, time.Now()))
+	require.NoError(t, s.Follow(ctx, "address", "address_1", 1, time.Now()))
+
 	now := time.Now()
 	yersterday := time.Now().Add(-time.Hour * 24)
 
-	require.NoError(t, s.AddPDV(ctx, "address", storage.PDVDenominator, time.Time{}))
-	require.NoError(t, s.AddPDV(ctx, "address_1", storage.PDVDenominator, time.Time{}))
-
-	require.NoError(t, s.AddPDV(ctx, "address", 10, yersterday))
-	require.NoError(t, s.AddPDV(ctx, "address", 10, now))
-	require.NoError(t, s.AddPDV(ctx, "address_1", 10, yersterday))
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "address", Amount: storage.PDVDenominator, Source: storage.RewardPDVSource, Timestamp: time.Time{}}))
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "address_1", Amount: storage.PDVDenominator, Source: storage.RewardPDVSource, Timestamp: time.Time{}}))
 
-	require.NoError(t, s.RefreshViews(ctx, true, true))
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "address", Amount: 10, Source: storage.RewardPDVSource, Timestamp: yersterday}))
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "address", Amount: 10, Source: storage.RewardPDVSource, Timestamp: now}))
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "address_1", Amount: 10, Source: storage.RewardPDVSource, Timestamp: yersterday}))
 
 	pp, err := s.GetProfileStats(ctx, "address", "address_1", "address_2")
 	require.NoError(t, err)
 	require.Len(t, pp, 3)
 
 	assert.EqualValues(t, &storage.ProfileStats{
-		Address:    "address",
-		PostsCount: 1,
+		Address:        "address",
+		PostsCount:     1,
+		FollowersCount: 2,
+		FollowingCount: 1,
+		LikesCount:     1,
+		DislikesCount:  1,
 		Stats: storage.PostStats{
 			"0001-01-01":                    1000000,
 			yersterday.Format("2006-01-02"): 1000010,
@@ -201,17 +352,20 @@ func TestPg_GetProfileStats(t *testing.T) {
 		},
 	}, pp[0])
 	assert.EqualValues(t, &storage.ProfileStats{
-		Address:    "address_1",
-		PostsCount: 0,
+		Address:        "address_1",
+		PostsCount:     0,
+		FollowersCount: 1,
+		FollowingCount: 1,
 		Stats: storage.PostStats{
 			"0001-01-01":                    1000000,
 			yersterday.Format("2006-01-02"): 1000010,
 		},
 	}, pp[1])
 	assert.EqualValues(t, &storage.ProfileStats{
-		Address:    "address_2",
-		PostsCount: 0,
-		Stats:      storage.PostStats{},
+		Address:        "address_2",
+		PostsCount:     0,
+		FollowingCount: 1,
+		Stats:          storage.PostStats{},
 	}, pp[2])
 }
 
@@ -229,7 +383,6 @@ func TestPg_CreatePost(t *testing.T) {
 	}
 
 	require.NoError(t, s.CreatePost(ctx, &expected))
-	require.NoError(t, s.RefreshViews(ctx, true, true))
 
 	p, err := s.GetPost(ctx, storage.PostID{expected.Owner, expected.UUID})
 	require.NoError(t, err)
@@ -257,7 +410,6 @@ func TestPg_GetPostBySlug(t *testing.T) {
 	}
 
 	require.NoError(t, s.CreatePost(ctx, &expected))
-	require.NoError(t, s.RefreshViews(ctx, true, true))
 	p, err := s.GetPost(ctx, storage.PostID{expected.Owner, expected.UUID})
 	require.NoError(t, err)
 	require.NotEmpty(t, p.Slug)
@@ -297,10 +449,8 @@ func TestPg_DeletePost(t *testing.T) {
 	}
 
 	require.NoError(t, s.CreatePost(ctx, &p))
-	require.NoError(t, s.RefreshViews(ctx, true, true))
 
 	require.NoError(t, s.DeletePost(ctx, storage.PostID{p.Owner, p.UUID}, p.CreatedAt, "moderator"))
-	require.NoError(t, s.RefreshViews(ctx, true, true))
 
 	_, err := s.GetPost(ctx, storage.PostID{p.Owner, p.UUID})
 	require.Equal(t, storage.ErrNotFound, err)
@@ -326,8 +476,6 @@ func TestPg_GetLiked(t *testing.T) {
 
 	require.NoError(t, s.SetLike(ctx, storage.PostID{"1", "1"}, -1, time.Now(), "3"))
 
-	require.NoError(t, s.RefreshViews(ctx, true, true))
-
 	likes, err := s.GetLikes(ctx, "3", storage.PostID{"1", "1"}, storage.PostID{"2", "2"})
 	require.NoError(t, err)
 	require.Len(t, likes, 1)
@@ -354,7 +502,6 @@ func TestPg_SetLike(t *testing.T) {
 	require.NoError(t, s.SetLike(ctx, storage.PostID{p.Owner, p.UUID}, 1, p.CreatedAt, "liker"))
 	require.NoError(t, s.SetLike(ctx, storage.PostID{p.Owner, p.UUID}, -1, p.CreatedAt, "liker2"))
 	require.NoError(t, s.SetLike(ctx, storage.PostID{p.Owner, p.UUID}, -1, p.CreatedAt, "liker3"))
-	require.NoError(t, s.RefreshViews(ctx, true, true))
 
 	post, err := s.GetPost(ctx, storage.PostID{p.Owner, p.UUID})
 	require.NoError(t, err)
@@ -362,38 +509,271 @@ func TestPg_SetLike(t *testing.T) {
 	require.EqualValues(t, 1, post.Likes)
 	require.EqualValues(t, 2, post.Dislikes)
 	require.EqualValues(t, -1, post.UPDV)
+
+	// changed like is counted once
+	require.NoError(t, s.SetLike(ctx, storage.PostID{p.Owner, p.UUID}, 1, p.CreatedAt, "liker2"))
+	require.NoError(t, s.SetLike(ctx, storage.PostID{p.Owner, p.UUID}, 0, p.CreatedAt, "liker3"))
+
+	post, err = s.GetPost(ctx, storage.PostID{p.Owner, p.UUID})
+	require.NoError(t, err)
+
+	require.EqualValues(t, 2, post.Likes)
+	require.EqualValues(t, 0, post.Dislikes)
+	require.EqualValues(t, 2, post.UPDV)
+
+	stats, err := s.GetPostStats(ctx, storage.PostID{p.Owner, p.UUID})
+	require.NoError(t, err)
+	require.Equal(t, storage.PostStats{p.CreatedAt.Format("2006-01-02"): 2}, stats[storage.PostID{p.Owner, p.UUID}])
+}
+
+func TestPg_SearchPosts(t *testing.T) {
+	defer cleanup(t)
+
+	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{
+		UUID: "1", Owner: "1", Category: 1, Title: "decentr news", Text: "nothing special", CreatedAt: time.Unix(1, 0),
+	}))
+	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{
+		UUID: "2", Owner: "2", Category: 2, Title: "weekly digest", Text: "decentr has released new browser", CreatedAt: time.Unix(2, 0),
+	}))
+	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{
+		UUID: "3", Owner: "3", Category: 1, Title: "bitcoin", Text: "decentr and bitcoin", CreatedAt: time.Unix(3, 0),
+	}))
+
+	ids := func(r []*storage.SearchResult) []string {
+		out := make([]string, len(r))
+		for i, v := range r {
+			out[i] = v.Post.UUID
+		}
+		return out
+	}
+
+	// title matches are ranked higher
+	r, err := s.SearchPosts(ctx, &storage.SearchPostsParams{Query: "decentr -bitcoin", Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, []string{"1", "2"}, ids(r))
+	require.Equal(t, "weekly digest", r[1].Post.Title)
+	require.Contains(t, r[1].Snippet, "<b>decentr</b>")
+
+	after := &storage.SearchCursor{Rank: r[0].Rank, Owner: r[0].Post.Owner, UUID: r[0].Post.UUID}
+	r, err = s.SearchPosts(ctx, &storage.SearchPostsParams{Query: "decentr -bitcoin", Limit: 10, After: after})
+	require.NoError(t, err)
+	require.Equal(t, []string{"2"}, ids(r))
+
+	// the page doesn't depend on the post listed last on the previous page
+	after.UUID = "0"
+	r, err = s.SearchPosts(ctx, &storage.SearchPostsParams{Query: "decentr -bitcoin", Limit: 10, After: after})
+	require.NoError(t, err)
+	require.Equal(t, []string{"2"}, ids(r))
+
+	c := community.Category(1)
+	r, err = s.SearchPosts(ctx, &storage.SearchPostsParams{Query: "decentr", Category: &c, Limit: 10})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"1", "3"}, ids(r))
+
+	q := "bitcoin"
+	p, err := s.ListPosts(ctx, &storage.ListPostsParams{
+		SortBy: storage.CreatedAtSortType, OrderBy: storage.DescendingOrder, Query: &q, Limit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, p, 1)
+	require.Equal(t, "3", p[0].UUID)
+}
+
+func TestPg_ListLikes(t *testing.T) {
+	defer cleanup(t)
+
+	id := storage.PostID{Owner: "owner", UUID: "uuid"}
+	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: id.UUID, Owner: id.Owner, CreatedAt: time.Unix(1, 0)}))
+
+	require.NoError(t, s.SetLike(ctx, id, 1, time.Unix(1, 0), "1"))
+	require.NoError(t, s.SetLike(ctx, id, -1, time.Unix(2, 0), "2"))
+	require.NoError(t, s.SetLike(ctx, id, 1, time.Unix(2, 0), "3"))
+	require.NoError(t, s.SetLike(ctx, id, 1, time.Unix(3, 0), "4"))
+	require.NoError(t, s.SetLike(ctx, id, 0, time.Unix(4, 0), "4"))
+
+	likers := func(l []*storage.Like) []string {
+		out := make([]string, len(l))
+		for i, v := range l {
+			out[i] = v.LikedBy
+		}
+		return out
+	}
+
+	l, err := s.ListLikes(ctx, &storage.ListLikesParams{Post: id, Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, []string{"3", "2"}, likers(l))
+	require.Equal(t, &storage.Like{
+		Post:    id,
+		LikedBy: "3",
+		Weight:  community.LikeWeight_LIKE_WEIGHT_UP,
+		LikedAt: time.Unix(2, 0).UTC(),
+	}, l[0])
+
+	after := &storage.LikesCursor{LikedAt: l[1].LikedAt, LikedBy: "2"}
+	l, err = s.ListLikes(ctx, &storage.ListLikesParams{Post: id, Limit: 2, After: after})
+	require.NoError(t, err)
+	require.Equal(t, []string{"1"}, likers(l))
+
+	// the page doesn't depend on the like listed last on the previous page, a moved like isn't listed twice
+	require.NoError(t, s.SetLike(ctx, id, 1, time.Unix(5, 0), "2"))
+	l, err = s.ListLikes(ctx, &storage.ListLikesParams{Post: id, Limit: 2, After: after})
+	require.NoError(t, err)
+	require.Equal(t, []string{"1"}, likers(l))
+
+	weight := community.LikeWeight_LIKE_WEIGHT_UP
+	l, err = s.ListLikes(ctx, &storage.ListLikesParams{Post: id, Weight: &weight, Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, []string{"3", "1"}, likers(l))
 }
 
 func TestPg_Follow(t *testing.T) {
 	defer cleanup(t)
 
-	require.NoError(t, s.Follow(ctx, "1", "2"))
+	require.NoError(t, s.Follow(ctx, "1", "2", 10, time.Unix(100, 0)))
 
 	var f struct {
-		Follower string `db:"follower"`
-		Followee string `db:"followee"`
+		Follower  string    `db:"follower"`
+		Followee  string    `db:"followee"`
+		Height    uint64    `db:"height"`
+		CreatedAt time.Time `db:"created_at"`
 	}
 
 	require.NoError(t, sqlx.NewDb(db, "postgres").GetContext(ctx, &f, `SELECT * FROM follow`))
 
 	require.Equal(t, "1", f.Follower)
 	require.Equal(t, "2", f.Followee)
+	require.EqualValues(t, 10, f.Height)
+	require.Equal(t, time.Unix(100, 0).UTC(), f.CreatedAt.UTC())
 }
 
 func TestPg_Unfollow(t *testing.T) {
 	defer cleanup(t)
 
-	require.NoError(t, s.Follow(ctx, "1", "2"))
-	require.NoError(t, s.Unfollow(ctx, "1", "2"))
+	require.NoError(t, s.Follow(ctx, "1", "2", 1, time.Now()))
+	require.NoError(t, s.Unfollow(ctx, "1", "2", 1, time.Now()))
 
 	var f struct {
 		Follower string `db:"follower"`
 		Followee string `db:"followee"`
 	}
 
-	err := sqlx.NewDb(db, "postgres").GetContext(ctx, &f, `SELECT * FROM follow`)
+	err := sqlx.NewDb(db, "postgres").GetContext(ctx, &f, `SELECT follower, followee FROM follow`)
 	require.Error(t, err)
 	require.True(t, errors.Is(err, sql.ErrNoRows))
+
+	var events []bool
+	require.NoError(t, sqlx.NewDb(db, "postgres").SelectContext(ctx, &events, `SELECT followed FROM follow_event ORDER BY id`))
+	require.Equal(t, []bool{true, false}, events)
+}
+
+func TestPg_ListFollows(t *testing.T) {
+	defer cleanup(t)
+
+	require.NoError(t, s.Follow(ctx, "1", "0", 1, time.Unix(1, 0)))
+	require.NoError(t, s.Follow(ctx, "2", "0", 2, time.Unix(2, 0)))
+	require.NoError(t, s.Follow(ctx, "3", "0", 2, time.Unix(2, 0)))
+	require.NoError(t, s.Follow(ctx, "0", "3", 3, time.Unix(3, 0)))
+
+	addresses := func(f []*storage.Follow, follower bool) []string {
+		out := make([]string, len(f))
+		for i, v := range f {
+			out[i] = v.Followee
+			if follower {
+				out[i] = v.Follower
+			}
+		}
+		return out
+	}
+
+	f, err := s.ListFollowers(ctx, &storage.ListFollowsParams{Address: "0", Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, []string{"3", "2"}, addresses(f, true))
+	require.EqualValues(t, 2, f[0].Height)
+	require.Equal(t, time.Unix(2, 0).UTC(), f[0].CreatedAt)
+
+	after := &storage.FollowsCursor{CreatedAt: f[1].CreatedAt, Address: "2"}
+	f, err = s.ListFollowers(ctx, &storage.ListFollowsParams{Address: "0", Limit: 2, After: after})
+	require.NoError(t, err)
+	require.Equal(t, []string{"1"}, addresses(f, true))
+
+	// the page doesn't depend on the follow listed last on the previous page
+	require.NoError(t, s.Unfollow(ctx, "2", "0", 4, time.Unix(4, 0)))
+	f, err = s.ListFollowers(ctx, &storage.ListFollowsParams{Address: "0", Limit: 2, After: after})
+	require.NoError(t, err)
+	require.Equal(t, []string{"1"}, addresses(f, true))
+
+	f, err = s.ListFollowing(ctx, &storage.ListFollowsParams{Address: "0", Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, []string{"3"}, addresses(f, false))
+
+	c, err := s.GetFollowsCount(ctx, "0")
+	require.NoError(t, err)
+	require.Equal(t, &storage.FollowsCount{Followers: 3, Following: 1}, c)
+
+	followed, err := s.GetFollowed(ctx, "0", "1", "3")
+	require.NoError(t, err)
+	require.Equal(t, map[string]bool{"3": true}, followed)
+}
+
+func TestPg_GetFollowersStats(t *testing.T) {
+	defer cleanup(t)
+
+	day := func(d int) time.Time { return time.Date(2021, 1, d, 12, 0, 0, 0, time.UTC) }
+
+	require.NoError(t, s.Follow(ctx, "1", "2", 1, day(1)))
+	require.NoError(t, s.Follow(ctx, "3", "2", 2, day(1)))
+	require.NoError(t, s.Follow(ctx, "1", "2", 3, day(2))) // already followed
+	require.NoError(t, s.Unfollow(ctx, "1", "2", 4, day(3)))
+	require.NoError(t, s.Unfollow(ctx, "4", "2", 5, day(3))) // not followed
+	require.NoError(t, s.Follow(ctx, "2", "1", 6, day(3)))
+
+	stats, err := s.GetFollowersStats(ctx, "2")
+	require.NoError(t, err)
+	require.Equal(t, storage.FollowersStats{
+		"2021-01-01": 2,
+		"2021-01-03": 1,
+	}, stats)
+
+	stats, err = s.GetFollowersStats(ctx, "5")
+	require.NoError(t, err)
+	require.Empty(t, stats)
+}
+
+func TestPg_ListFollowSuggestions(t *testing.T) {
+	defer cleanup(t)
+
+	now := time.Now().UTC()
+
+	for _, v := range [][2]string{
+		{"address", "1"},
+		{"address", "2"},
+		{"1", "3"},
+		{"2", "3"},
+		{"1", "4"},
+		{"1", "2"},       // already followed
+		{"1", "address"}, // the address itself
+	} {
+		require.NoError(t, s.Follow(ctx, v[0], v[1], 1, now))
+	}
+
+	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: "1", Owner: "4", CreatedAt: now}))
+	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: "2", Owner: "5", CreatedAt: now}))
+	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: "3", Owner: "6", CreatedAt: now}))
+	require.NoError(t, s.SetLike(ctx, storage.PostID{"4", "1"}, 1, now, "address"))
+	require.NoError(t, s.SetLike(ctx, storage.PostID{"5", "2"}, 1, now, "address"))
+	require.NoError(t, s.SetLike(ctx, storage.PostID{"6", "3"}, -1, now, "address"))
+
+	res, err := s.ListFollowSuggestions(ctx, "address", 10)
+	require.NoError(t, err)
+	require.Equal(t, []*storage.FollowSuggestion{
+		{Address: "3", FollowedByFollowees: 2},
+		{Address: "4", FollowedByFollowees: 1, LikedPosts: 1},
+		{Address: "5", LikedPosts: 1},
+	}, res)
+
+	res, err = s.ListFollowSuggestions(ctx, "address", 1)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
 }
 
 func TestPg_ListPosts(t *testing.T) {
@@ -405,8 +785,8 @@ func TestPg_ListPosts(t *testing.T) {
 	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: "4", Owner: "4", Category: 4, CreatedAt: time.Unix(4, 0)}))
 	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: "5", Owner: "5", Category: 5, CreatedAt: time.Unix(5, 0)}))
 
-	require.NoError(t, s.Follow(ctx, "1", "2"))
-	require.NoError(t, s.Follow(ctx, "1", "3"))
+	require.NoError(t, s.Follow(ctx, "1", "2", 1, time.Now()))
+	require.NoError(t, s.Follow(ctx, "1", "3", 1, time.Now()))
 
 	require.NoError(t, s.SetLike(ctx, storage.PostID{"5", "5"}, 1, time.Unix(1, 0), "13"))
 	require.NoError(t, s.SetLike(ctx, storage.PostID{"5", "5"}, 1, time.Unix(1, 0), "3"))
@@ -443,15 +823,17 @@ func TestPg_ListPosts(t *testing.T) {
 	require.NoError(t, s.SetLike(ctx, storage.PostID{"4", "4"}, -1, time.Unix(1, 0), "17"))
 	require.NoError(t, s.SetLike(ctx, storage.PostID{"4", "4"}, -1, time.Unix(1, 0), "18"))
 
-	require.NoError(t, s.RefreshViews(ctx, true, true))
-
 	cat := community.Category(3)
 	owner := "2"
 	likedBy := "5"
 	followedBy := "1"
 	from := uint64(2)
 	to := uint64(5)
-	after := storage.PostID{Owner: "2", UUID: "2"}
+
+	p2, err := s.GetPost(ctx, storage.PostID{Owner: "2", UUID: "2"})
+	require.NoError(t, err)
+	afterCreatedAt := storage.PostsCursor{Value: p2.CreatedAt.UnixMicro(), Owner: p2.Owner, UUID: p2.UUID}
+	afterPDV := storage.PostsCursor{Value: p2.UPDV, Owner: p2.Owner, UUID: p2.UUID}
 
 	tt := []struct {
 		name string
@@ -565,6 +947,18 @@ func TestPg_ListPosts(t *testing.T) {
 			},
 			ids: []string{"2", "3"},
 		},
+		{
+			name: "followed_by_from_to_desc",
+			p: storage.ListPostsParams{
+				SortBy:     storage.CreatedAtSortType,
+				OrderBy:    storage.DescendingOrder,
+				Limit:      100,
+				FollowedBy: &followedBy,
+				From:       &from,
+				To:         &to,
+			},
+			ids: []string{"3"},
+		},
 		{
 			name: "from_to",
 			p: storage.ListPostsParams{
@@ -582,7 +976,7 @@ func TestPg_ListPosts(t *testing.T) {
 				SortBy:  storage.CreatedAtSortType,
 				OrderBy: storage.AscendingOrder,
 				Limit:   100,
-				After:   &after,
+				After:   &afterCreatedAt,
 			},
 			ids: []string{"3", "4", "5"},
 		},
@@ -592,7 +986,7 @@ func TestPg_ListPosts(t *testing.T) {
 				SortBy:  storage.PDVSortType,
 				OrderBy: storage.DescendingOrder,
 				Limit:   100,
-				After:   &after,
+				After:   &afterPDV,
 			},
 			ids: []string{"1", "4"},
 		},
@@ -602,7 +996,7 @@ func TestPg_ListPosts(t *testing.T) {
 				SortBy:  storage.PDVSortType,
 				OrderBy: storage.AscendingOrder,
 				Limit:   100,
-				After:   &after,
+				After:   &afterPDV,
 			},
 			ids: []string{"3", "5"},
 		},
@@ -621,6 +1015,276 @@ func TestPg_ListPosts(t *testing.T) {
 	}
 }
 
+func TestPg_ListPosts_Hot(t *testing.T) {
+	defer cleanup(t)
+
+	now := time.Now().UTC()
+
+	// old post needs 10 times more net likes per 12.5 hours of age
+	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: "1", Owner: "1", CreatedAt: now.Add(-25 * time.Hour)}))
+	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: "2", Owner: "2", CreatedAt: now.Add(-time.Hour)}))
+	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: "3", Owner: "3", CreatedAt: now}))
+
+	for i := 0; i < 50; i++ {
+		require.NoError(t, s.SetLike(ctx, storage.PostID{"1", "1"}, 1, now.Add(-48*time.Hour), fmt.Sprintf("l%d", i)))
+	}
+	for i := 0; i < 5; i++ {
+		require.NoError(t, s.SetLike(ctx, storage.PostID{"2", "2"}, 1, now, fmt.Sprintf("l%d", i)))
+	}
+	for i := 0; i < 3; i++ {
+		require.NoError(t, s.SetLike(ctx, storage.PostID{"3", "3"}, -1, now, fmt.Sprintf("l%d", i)))
+	}
+
+	// scores are log10(5)-0.08=0.62, log10(50)-2=-0.3 and -log10(3)=-0.48
+	p, err := s.ListPosts(ctx, &storage.ListPostsParams{SortBy: storage.HotSortType, OrderBy: storage.DescendingOrder, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, p, 2)
+	require.Equal(t, "2", p[0].UUID)
+	require.Equal(t, "1", p[1].UUID)
+	require.Greater(t, p[0].Score, p[1].Score)
+
+	p, err = s.ListPosts(ctx, &storage.ListPostsParams{
+		SortBy:  storage.HotSortType,
+		OrderBy: storage.DescendingOrder,
+		Limit:   2,
+		After:   &storage.PostsCursor{Score: p[1].Score, Owner: p[1].Owner, UUID: p[1].UUID},
+	})
+	require.NoError(t, err)
+	require.Len(t, p, 1)
+	require.Equal(t, "3", p[0].UUID)
+
+	// post 1 has no likes during the last day, post 3 has negative ones
+	p, err = s.ListPosts(ctx, &storage.ListPostsParams{SortBy: storage.RisingSortType, OrderBy: storage.DescendingOrder, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, p, 1)
+	require.Equal(t, "2", p[0].UUID)
+	require.Greater(t, p[0].Score, float64(0))
+
+	// rising scores are calculated at the reference time, so they don't change between pages
+	at := func(now time.Time) float64 {
+		p, err := s.ListPosts(ctx, &storage.ListPostsParams{
+			SortBy: storage.RisingSortType, OrderBy: storage.DescendingOrder, Limit: 10, Now: now,
+		})
+		require.NoError(t, err)
+		require.Len(t, p, 1)
+		return p[0].Score
+	}
+	require.Equal(t, at(now.Add(time.Minute)), at(now.Add(time.Minute)))
+	require.Greater(t, at(now.Add(time.Minute)), at(now.Add(time.Hour)))
+}
+
+func TestPg_ListPosts_Best(t *testing.T) {
+	defer cleanup(t)
+
+	now := time.Now().UTC()
+
+	// likes and dislikes per post
+	votes := map[string][2]int{
+		"1": {1, 0},
+		"2": {10, 0},
+		"3": {5, 5},
+		"4": {6, 2},
+	}
+	for id, v := range votes {
+		require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: id, Owner: id, CreatedAt: now}))
+		for i := 0; i < v[0]; i++ {
+			require.NoError(t, s.SetLike(ctx, storage.PostID{id, id}, 1, now, fmt.Sprintf("l%d", i)))
+		}
+		for i := 0; i < v[1]; i++ {
+			require.NoError(t, s.SetLike(ctx, storage.PostID{id, id}, -1, now, fmt.Sprintf("d%d", i)))
+		}
+	}
+
+	list := func(sortBy storage.SortType, after *storage.PostsCursor) []*storage.Post {
+		p, err := s.ListPosts(ctx, &storage.ListPostsParams{
+			SortBy:  sortBy,
+			OrderBy: storage.DescendingOrder,
+			Limit:   2,
+			After:   after,
+		})
+		require.NoError(t, err)
+		return p
+	}
+
+	// wilson lower bounds are 0.72, 0.41, 0.24 and 0.21
+	p := list(storage.BestSortType, nil)
+	require.Len(t, p, 2)
+	require.Equal(t, "2", p[0].UUID)
+	require.Equal(t, "4", p[1].UUID)
+
+	p = list(storage.BestSortType, &storage.PostsCursor{Score: p[1].Score, Owner: p[1].Owner, UUID: p[1].UUID})
+	require.Len(t, p, 2)
+	require.Equal(t, "3", p[0].UUID)
+	require.Equal(t, "1", p[1].UUID)
+
+	// controversial scores are 10, 2 and 0 for posts without dislikes
+	p = list(storage.ControversialSortType, nil)
+	require.Len(t, p, 2)
+	require.Equal(t, "3", p[0].UUID)
+	require.Equal(t, "4", p[1].UUID)
+	require.InDelta(t, 10, p[0].Score, 0.001)
+	require.InDelta(t, 2, p[1].Score, 0.001)
+}
+
+func TestPg_Timeline(t *testing.T) {
+	defer cleanup(t)
+
+	now := time.Now().UTC()
+
+	listFollowed := func() []string {
+		followedBy := "follower"
+		p, err := s.ListPosts(ctx, &storage.ListPostsParams{
+			SortBy:     storage.CreatedAtSortType,
+			OrderBy:    storage.DescendingOrder,
+			Limit:      10,
+			FollowedBy: &followedBy,
+		})
+		require.NoError(t, err)
+
+		out := make([]string, len(p))
+		for i, v := range p {
+			out[i] = v.UUID
+		}
+		return out
+	}
+
+	// backfill on follow
+	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: "1", Owner: "1", CreatedAt: now.Add(-time.Hour)}))
+	require.NoError(t, s.Follow(ctx, "follower", "1", 1, now))
+	require.Equal(t, []string{"1"}, listFollowed())
+
+	// fan-out on create
+	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: "2", Owner: "1", CreatedAt: now}))
+	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: "3", Owner: "2", CreatedAt: now}))
+	require.Equal(t, []string{"2", "1"}, listFollowed())
+
+	// cleanup on delete
+	require.NoError(t, s.DeletePost(ctx, storage.PostID{"1", "2"}, now, "1"))
+	require.Equal(t, []string{"1"}, listFollowed())
+
+	// cleanup on unfollow
+	require.NoError(t, s.Unfollow(ctx, "follower", "1", 2, now))
+	require.Empty(t, listFollowed())
+
+	// cleanup on reset account
+	require.NoError(t, s.Follow(ctx, "follower", "2", 3, now))
+	require.Equal(t, []string{"3"}, listFollowed())
+	require.NoError(t, s.InTx(ctx, func(s storage.Storage) error {
+		return s.ResetAccount(ctx, "follower")
+	}))
+	require.Empty(t, listFollowed())
+}
+
+func TestPg_ListRelatedPosts(t *testing.T) {
+	defer cleanup(t)
+
+	for i := 1; i <= 4; i++ {
+		id := strconv.Itoa(i)
+		require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: id, Owner: id, CreatedAt: time.Now()}))
+	}
+
+	// post 2 is liked together with post 1 twice, post 3 once, post 4 is disliked
+	for _, v := range []struct {
+		post   string
+		weight community.LikeWeight
+		by     string
+	}{
+		{"1", 1, "a"}, {"2", 1, "a"}, {"3", 1, "a"}, {"4", -1, "a"},
+		{"1", 1, "b"}, {"2", 1, "b"},
+		{"3", 1, "c"},
+	} {
+		require.NoError(t, s.SetLike(ctx, storage.PostID{v.post, v.post}, v.weight, time.Now(), v.by))
+	}
+
+	require.NoError(t, s.RefreshRelatedPosts(ctx))
+
+	p, err := s.ListRelatedPosts(ctx, storage.PostID{"1", "1"}, 10)
+	require.NoError(t, err)
+	require.Len(t, p, 2)
+	require.Equal(t, "2", p[0].UUID)
+	require.Equal(t, "3", p[1].UUID)
+
+	p, err = s.ListRelatedPosts(ctx, storage.PostID{"1", "1"}, 1)
+	require.NoError(t, err)
+	require.Len(t, p, 1)
+
+	p, err = s.ListRelatedPosts(ctx, storage.PostID{"4", "4"}, 10)
+	require.NoError(t, err)
+	require.Empty(t, p)
+}
+
+func TestPg_ListFeed(t *testing.T) {
+	defer cleanup(t)
+
+	now := time.Now().UTC()
+
+	for i, v := range []struct {
+		owner    string
+		category community.Category
+	}{
+		{"followee", 1}, // 1: followee's post
+		{"other", 2},    // 2: liked by followee
+		{"other", 3},    // 3: popular in the address' category
+		{"other", 3},    // 4: not popular in the address' category
+		{"other", 4},    // 5: popular in another category
+		{"address", 3},  // 6: own post
+		{"followee", 2}, // 7: followee's post liked by followee
+	} {
+		require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{
+			UUID:      strconv.Itoa(i + 1),
+			Owner:     v.owner,
+			Category:  v.category,
+			CreatedAt: now.Add(-time.Duration(i) * time.Hour),
+		}))
+	}
+
+	require.NoError(t, s.Follow(ctx, "address", "followee", 1, now))
+	require.NoError(t, s.SetLike(ctx, storage.PostID{"other", "2"}, 1, now, "followee"))
+	require.NoError(t, s.SetLike(ctx, storage.PostID{"followee", "7"}, 1, now, "followee"))
+	require.NoError(t, s.SetLike(ctx, storage.PostID{"other", "3"}, 1, now, "address"))
+	require.NoError(t, s.SetLike(ctx, storage.PostID{"other", "5"}, 1, now, "another"))
+
+	p, err := s.ListFeed(ctx, &storage.ListFeedParams{Address: "address", Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, p, 2)
+	require.Equal(t, "1", p[0].UUID)
+	require.Equal(t, "2", p[1].UUID)
+
+	p, err = s.ListFeed(ctx, &storage.ListFeedParams{
+		Address: "address",
+		Limit:   10,
+		After:   &storage.PostsCursor{Score: p[1].Score, Owner: p[1].Owner, UUID: p[1].UUID},
+	})
+	require.NoError(t, err)
+	require.Len(t, p, 2)
+	require.Equal(t, "3", p[0].UUID)
+	require.Equal(t, "7", p[1].UUID)
+}
+
+func TestPg_ListFeed_CategoryPostsCount(t *testing.T) {
+	defer cleanup(t)
+
+	now := time.Now().UTC()
+
+	for i := 0; i <= feedCategoryPostsCount; i++ {
+		id := storage.PostID{Owner: "other", UUID: strconv.Itoa(i)}
+		require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{
+			UUID:      id.UUID,
+			Owner:     id.Owner,
+			Category:  1,
+			CreatedAt: now.Add(-time.Duration(i) * time.Minute),
+		}))
+		require.NoError(t, s.SetLike(ctx, id, 1, now, "another"))
+	}
+	require.NoError(t, s.SetLike(ctx, storage.PostID{Owner: "other", UUID: "0"}, 1, now, "address"))
+
+	// only the hottest posts of the category are added, the oldest one is not
+	p, err := s.ListFeed(ctx, &storage.ListFeedParams{Address: "address", Limit: feedCategoryPostsCount + 1})
+	require.NoError(t, err)
+	require.Len(t, p, feedCategoryPostsCount)
+	require.Equal(t, strconv.Itoa(feedCategoryPostsCount-1), p[len(p)-1].UUID)
+}
+
 func TestPg_GetStats(t *testing.T) {
 	defer cleanup(t)
 
@@ -639,8 +1303,6 @@ func TestPg_GetStats(t *testing.T) {
 	require.NoError(t, s.SetLike(ctx, storage.PostID{"2", "2"}, 1, yesterday, "3"))
 	require.NoError(t, s.SetLike(ctx, storage.PostID{"2", "2"}, 1, monthAgo, "4"))
 
-	require.NoError(t, s.RefreshViews(ctx, true, true))
-
 	stats, err := s.GetPostStats(ctx, storage.PostID{"1", "1"}, storage.PostID{"2", "2"})
 	require.NoError(t, err)
 
@@ -658,10 +1320,172 @@ func TestPg_GetStats(t *testing.T) {
 	}, stats)
 }
 
+func TestPg_SetLike_History(t *testing.T) {
+	defer cleanup(t)
+
+	today := time.Now().UTC()
+	yesterday := today.Add(-time.Hour * 24)
+	id := storage.PostID{Owner: "1", UUID: "1"}
+
+	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: id.UUID, Owner: id.Owner, Category: 1, CreatedAt: yesterday}))
+	require.NoError(t, s.SetLike(ctx, id, community.LikeWeight_LIKE_WEIGHT_UP, yesterday, "2"))
+	require.NoError(t, s.SetLike(ctx, id, community.LikeWeight_LIKE_WEIGHT_DOWN, today, "2"))
+
+	var events int
+	require.NoError(t, sqlx.GetContext(ctx, sqlx.NewDb(db, "postgres"), &events, `SELECT COUNT(*) FROM like_event`))
+	require.Equal(t, 2, events)
+
+	// the like is kept on its own day
+	stats, err := s.GetPostStats(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, storage.PostStats{
+		yesterday.Format("2006-01-02"): 1,
+		today.Format("2006-01-02"):     -1,
+	}, stats[id])
+
+	require.NoError(t, s.InTx(ctx, func(s storage.Storage) error {
+		return s.ResetAccount(ctx, "2")
+	}))
+
+	require.NoError(t, sqlx.GetContext(ctx, sqlx.NewDb(db, "postgres"), &events, `SELECT COUNT(*) FROM like_event`))
+	require.Zero(t, events)
+
+	stats, err = s.GetPostStats(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, storage.PostStats{
+		yesterday.Format("2006-01-02"): 0,
+		today.Format("2006-01-02"):     0,
+	}, stats[id])
+}
+
 func TestPg_AddPDV(t *testing.T) {
 	defer cleanup(t)
 
-	require.NoError(t, s.AddPDV(ctx, "addr", 10, time.Now()))
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "addr", Amount: 10, Source: storage.RewardPDVSource, Timestamp: time.Now()}))
+}
+
+func TestPg_ListPDV(t *testing.T) {
+	defer cleanup(t)
+
+	timestamp := time.Now().UTC().Truncate(time.Second)
+
+	pdv := []*storage.PDV{
+		{Address: "addr", Amount: 10, Source: storage.GenesisPDVSource, Timestamp: timestamp},
+		{Address: "addr", Amount: 5, Source: storage.RewardPDVSource, Counterparty: "supervisor", Timestamp: timestamp},
+		{Address: "addr2", Amount: 5, Source: storage.RewardPDVSource, Counterparty: "supervisor", Timestamp: timestamp},
+		{Address: "addr", Amount: -1, Source: storage.LikePDVSource, Post: &storage.PostID{Owner: "addr", UUID: "1"},
+			Counterparty: "liker", Timestamp: timestamp},
+	}
+	for _, v := range pdv {
+		require.NoError(t, s.AddPDV(ctx, v))
+	}
+
+	list, err := s.ListPDV(ctx, &storage.ListPDVParams{Address: "addr", Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	for i, v := range []*storage.PDV{pdv[3], pdv[1]} {
+		v.ID = list[i].ID
+		assert.Equal(t, v, list[i])
+	}
+
+	list, err = s.ListPDV(ctx, &storage.ListPDVParams{Address: "addr", Limit: 2, After: &list[1].ID})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, storage.GenesisPDVSource, list[0].Source)
+	assert.Nil(t, list[0].Post)
+}
+
+func TestPg_TokenMovements(t *testing.T) {
+	defer cleanup(t)
+
+	today := time.Now().UTC().Truncate(time.Second)
+	yesterday := today.Add(-time.Hour * 24)
+
+	movements := []*storage.TokenMovement{
+		{Type: storage.MintTokenMovementType, Address: "addr", Denom: "udec", Amount: 100, Timestamp: yesterday},
+		{Type: storage.BurnTokenMovementType, Address: "addr", Denom: "udec", Amount: 10, Timestamp: yesterday},
+		{Type: storage.MintTokenMovementType, Address: "addr2", Denom: "other", Amount: 5, Timestamp: today},
+		{Type: storage.BurnTokenMovementType, Address: "addr2", Denom: "udec", Amount: 30, Timestamp: today},
+	}
+	for _, v := range movements {
+		require.NoError(t, s.AddTokenMovement(ctx, v))
+	}
+
+	denom := "udec"
+	list, err := s.ListTokenMovements(ctx, &storage.ListTokenMovementsParams{Denom: &denom, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, movements[3].Amount, list[0].Amount)
+	assert.Equal(t, today, list[0].Timestamp)
+	assert.Equal(t, movements[1].Amount, list[1].Amount)
+
+	list, err = s.ListTokenMovements(ctx, &storage.ListTokenMovementsParams{Denom: &denom, Limit: 2, After: &list[1].ID})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, storage.MintTokenMovementType, list[0].Type)
+
+	address := "addr2"
+	list, err = s.ListTokenMovements(ctx, &storage.ListTokenMovementsParams{Address: &address, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	stats, err := s.GetSupplyStats(ctx, "udec")
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	assert.EqualValues(t, 100, stats[0].Minted)
+	assert.EqualValues(t, 10, stats[0].Burned)
+	assert.EqualValues(t, 0, stats[1].Minted)
+	assert.EqualValues(t, 30, stats[1].Burned)
+}
+
+func TestPg_GetBalances(t *testing.T) {
+	defer cleanup(t)
+
+	today := time.Now().UTC()
+	yesterday := today.Add(-time.Hour * 24)
+
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "addr", Amount: 10, Source: storage.RewardPDVSource, Timestamp: yesterday}))
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "addr", Amount: 5, Source: storage.RewardPDVSource, Timestamp: today}))
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "addr2", Amount: 5, Source: storage.RewardPDVSource, Timestamp: today}))
+	// pdv of likes doesn't change the balance
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{
+		Address: "addr", Amount: 1, Source: storage.LikePDVSource,
+		Post: &storage.PostID{Owner: "addr", UUID: "uuid"}, Counterparty: "addr2", Timestamp: today,
+	}))
+	require.NoError(t, s.AddTokenMovement(ctx, &storage.TokenMovement{
+		Type: storage.MintTokenMovementType, Address: "addr", Denom: "udec", Amount: 100, Timestamp: yesterday,
+	}))
+	require.NoError(t, s.AddTokenMovement(ctx, &storage.TokenMovement{
+		Type: storage.BurnTokenMovementType, Address: "addr", Denom: "udec", Amount: 30, Timestamp: today,
+	}))
+
+	b, err := s.GetBalances(ctx, "addr")
+	require.NoError(t, err)
+	assert.Equal(t, []*storage.Balance{
+		{Denom: "udec", Amount: 70, History: storage.BalanceHistory{
+			yesterday.Format("2006-01-02"): 100,
+			today.Format("2006-01-02"):     70,
+		}},
+		{Denom: storage.PDVDenom, Amount: 15, History: storage.BalanceHistory{
+			yesterday.Format("2006-01-02"): 10,
+			today.Format("2006-01-02"):     15,
+		}},
+	}, b)
+
+	// pdv balance is reset
+	require.NoError(t, s.InTx(ctx, func(s storage.Storage) error {
+		return s.ResetAccount(ctx, "addr")
+	}))
+
+	b, err = s.GetBalances(ctx, "addr")
+	require.NoError(t, err)
+	require.Len(t, b, 1)
+	assert.Equal(t, "udec", b[0].Denom)
+
+	b, err = s.GetBalances(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Empty(t, b)
 }
 
 func TestPg_GetDecentrStats(t *testing.T) {
@@ -670,11 +1494,12 @@ func TestPg_GetDecentrStats(t *testing.T) {
 	today := time.Now().UTC()
 	yesterday := today.Add(-time.Hour * 24)
 	monthAgo := today.Add(-time.Hour * 24 * 32)
-	require.NoError(t, s.AddPDV(ctx, "addr2", 5, today))
-	require.NoError(t, s.AddPDV(ctx, "addr2", -15, yesterday))
-	require.NoError(t, s.AddPDV(ctx, "addr", 10, today))
-	require.NoError(t, s.AddPDV(ctx, "addr", 10, yesterday))
-	require.NoError(t, s.AddPDV(ctx, "addr", 10, monthAgo))
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "addr2", Amount: 5, Source: storage.RewardPDVSource, Timestamp: today}))
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "addr2", Amount: -15, Source: storage.RewardPDVSource, Timestamp: yesterday}))
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "addr", Amount: 10, Source: storage.RewardPDVSource, Timestamp: today}))
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "addr", Amount: 10, Source: storage.RewardPDVSource, Timestamp: yesterday}))
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "addr", Amount: 10, Source: storage.RewardPDVSource, Timestamp: monthAgo}))
+	require.NoError(t, s.RefreshViews(ctx))
 
 	stats, err := s.GetDecentrStats(ctx)
 	require.NoError(t, err)
@@ -689,11 +1514,12 @@ func TestPg_ResetAccount(t *testing.T) {
 
 	require.NoError(t, s.InTx(context.Background(), func(s storage.Storage) error {
 		require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: "1", Owner: "1", Category: 1, CreatedAt: time.Now()}))
+		require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: "2", Owner: "2", Category: 1, CreatedAt: time.Now()}))
 		require.NoError(t, s.SetLike(ctx, storage.PostID{"1", "1"}, -1, time.Now(), "3"))
-		require.NoError(t, s.Follow(ctx, "1", "2"))
-		require.NoError(t, s.Follow(ctx, "2", "1"))
-		require.NoError(t, s.AddPDV(ctx, "1", 10, time.Now()))
-		require.NoError(t, s.RefreshViews(ctx, true, true))
+		require.NoError(t, s.SetLike(ctx, storage.PostID{"2", "2"}, 1, time.Now(), "1"))
+		require.NoError(t, s.Follow(ctx, "1", "2", 1, time.Now()))
+		require.NoError(t, s.Follow(ctx, "2", "1", 1, time.Now()))
+		require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "1", Amount: 10, Source: storage.RewardPDVSource, Timestamp: time.Now()}))
 
 		require.NoError(t, s.ResetAccount(ctx, "1"))
 
@@ -704,6 +1530,12 @@ func TestPg_ResetAccount(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, []*storage.ProfileStats{{Address: "1", Stats: storage.PostStats{}}}, stats)
 
+		// likes of the account are subtracted from counters
+		post, err := s.GetPost(ctx, storage.PostID{Owner: "2", UUID: "2"})
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, post.Likes)
+		assert.EqualValues(t, 0, post.UPDV)
+
 		return nil
 	}))
 }
@@ -720,11 +1552,12 @@ func TestPg_GetDDVStats(t *testing.T) {
 	today := time.Now().UTC()
 	yesterday := today.Add(-time.Hour * 24)
 
-	require.NoError(t, s.AddPDV(ctx, "addr2", 5, today))
-	require.NoError(t, s.AddPDV(ctx, "addr", 10, today))
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "addr2", Amount: 5, Source: storage.RewardPDVSource, Timestamp: today}))
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "addr", Amount: 10, Source: storage.RewardPDVSource, Timestamp: today}))
 
-	require.NoError(t, s.AddPDV(ctx, "addr2", -15, yesterday))
-	require.NoError(t, s.AddPDV(ctx, "addr", 10, yesterday))
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "addr2", Amount: -15, Source: storage.RewardPDVSource, Timestamp: yesterday}))
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "addr", Amount: 10, Source: storage.RewardPDVSource, Timestamp: yesterday}))
+	require.NoError(t, s.RefreshViews(ctx))
 
 	stats, err = s.GetDDVStats(ctx)
 	require.NoError(t, err)
@@ -734,6 +1567,129 @@ func TestPg_GetDDVStats(t *testing.T) {
 	require.Equal(t, int64(-5), stats[1].Value)
 }
 
+func TestPg_GetCategoryStats(t *testing.T) {
+	defer cleanup(t)
+
+	today := time.Now().UTC()
+	yesterday := today.Add(-time.Hour * 24)
+	longAgo := today.Add(-time.Hour * 24 * 100)
+
+	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: "1", Owner: "1", Category: 1, CreatedAt: today}))
+	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: "2", Owner: "2", Category: 1, CreatedAt: yesterday}))
+	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: "3", Owner: "1", Category: 1, CreatedAt: today}))
+	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: "4", Owner: "3", Category: 2, CreatedAt: longAgo}))
+	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: "5", Owner: "3", Category: 3, CreatedAt: today}))
+	require.NoError(t, s.DeletePost(ctx, storage.PostID{"3", "5"}, today, "3"))
+
+	require.NoError(t, s.SetLike(ctx, storage.PostID{"1", "1"}, 1, today, "2"))
+	require.NoError(t, s.SetLike(ctx, storage.PostID{"2", "2"}, 1, today, "1"))
+	require.NoError(t, s.SetLike(ctx, storage.PostID{"3", "4"}, 1, today, "1"))
+
+	require.NoError(t, s.RefreshViews(ctx))
+
+	stats, err := s.GetCategoryStats(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []*storage.CategoryStats{
+		{
+			Category:      1,
+			PostsCount:    3,
+			ActiveAuthors: 2,
+			LikesCount:    2,
+			Stats: storage.PostStats{
+				today.Format("2006-01-02"):     2,
+				yesterday.Format("2006-01-02"): 1,
+			},
+		},
+		{
+			Category:      2,
+			PostsCount:    1,
+			ActiveAuthors: 0,
+			LikesCount:    1,
+			Stats:         storage.PostStats{},
+		},
+	}, stats)
+}
+
+func TestPg_Leaderboard(t *testing.T) {
+	defer cleanup(t)
+
+	today := time.Now().UTC()
+	monthAgo := today.Add(-time.Hour * 24 * 40)
+
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "1", Amount: 10, Source: storage.RewardPDVSource, Timestamp: today}))
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "2", Amount: 10, Source: storage.RewardPDVSource, Timestamp: today}))
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "3", Amount: 5, Source: storage.RewardPDVSource, Timestamp: today}))
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "3", Amount: 50, Source: storage.RewardPDVSource, Timestamp: monthAgo}))
+
+	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: "1", Owner: "1", CreatedAt: monthAgo}))
+	require.NoError(t, s.CreatePost(ctx, &storage.CreatePostParams{UUID: "2", Owner: "2", CreatedAt: today}))
+	require.NoError(t, s.SetLike(ctx, storage.PostID{"1", "1"}, 1, today, "2"))
+	require.NoError(t, s.SetLike(ctx, storage.PostID{"1", "1"}, 1, today, "3"))
+	require.NoError(t, s.SetLike(ctx, storage.PostID{"2", "2"}, -1, today, "1"))
+	// the like is received before the period, so it isn't counted again when it's set again
+	require.NoError(t, s.SetLike(ctx, storage.PostID{"1", "1"}, 1, monthAgo, "4"))
+	require.NoError(t, s.SetLike(ctx, storage.PostID{"1", "1"}, 0, today, "4"))
+	require.NoError(t, s.SetLike(ctx, storage.PostID{"1", "1"}, 1, today, "4"))
+
+	require.NoError(t, s.RefreshViews(ctx))
+
+	list := func(p storage.ListLeaderboardParams) []*storage.LeaderboardItem {
+		res, err := s.ListLeaderboard(ctx, &p)
+		require.NoError(t, err)
+		return res
+	}
+
+	require.Equal(t, []*storage.LeaderboardItem{
+		{Address: "2", Value: 10, Rank: 1},
+		{Address: "1", Value: 10, Rank: 1},
+		{Address: "3", Value: 5, Rank: 3},
+	}, list(storage.ListLeaderboardParams{Metric: storage.PDVLeaderboardMetric, Period: storage.WeekLeaderboardPeriod, Limit: 10}))
+
+	after := &storage.LeaderboardCursor{Value: 10, Address: "2"}
+	require.Equal(t, []*storage.LeaderboardItem{
+		{Address: "1", Value: 10, Rank: 1},
+	}, list(storage.ListLeaderboardParams{Metric: storage.PDVLeaderboardMetric, Period: storage.DayLeaderboardPeriod, Limit: 1, After: after}))
+
+	// the page doesn't depend on the item listed last on the previous page
+	require.Equal(t, []*storage.LeaderboardItem{
+		{Address: "2", Value: 10, Rank: 1},
+	}, list(storage.ListLeaderboardParams{
+		Metric: storage.PDVLeaderboardMetric, Period: storage.DayLeaderboardPeriod, Limit: 1,
+		After: &storage.LeaderboardCursor{Value: 10, Address: "3"},
+	}))
+
+	require.Equal(t, []*storage.LeaderboardItem{
+		{Address: "3", Value: 55, Rank: 1},
+	}, list(storage.ListLeaderboardParams{Metric: storage.PDVLeaderboardMetric, Period: storage.AllLeaderboardPeriod, Limit: 1}))
+
+	require.Equal(t, []*storage.LeaderboardItem{
+		{Address: "1", Value: 2, Rank: 1},
+	}, list(storage.ListLeaderboardParams{Metric: storage.LikesLeaderboardMetric, Period: storage.MonthLeaderboardPeriod, Limit: 10}))
+
+	require.Equal(t, []*storage.LeaderboardItem{
+		{Address: "1", Value: 3, Rank: 1},
+	}, list(storage.ListLeaderboardParams{Metric: storage.LikesLeaderboardMetric, Period: storage.AllLeaderboardPeriod, Limit: 10}))
+
+	require.Equal(t, []*storage.LeaderboardItem{
+		{Address: "2", Value: 1, Rank: 1},
+	}, list(storage.ListLeaderboardParams{Metric: storage.PostsLeaderboardMetric, Period: storage.MonthLeaderboardPeriod, Limit: 10}))
+
+	item, err := s.GetLeaderboardItem(ctx, storage.PDVLeaderboardMetric, storage.WeekLeaderboardPeriod, "3")
+	require.NoError(t, err)
+	require.Equal(t, &storage.LeaderboardItem{Address: "3", Value: 5, Rank: 3}, item)
+
+	_, err = s.GetLeaderboardItem(ctx, storage.PostsLeaderboardMetric, storage.DayLeaderboardPeriod, "1")
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	// ranks are shifted by the refresh, but the next page stays the same
+	require.NoError(t, s.AddPDV(ctx, &storage.PDV{Address: "4", Amount: 20, Source: storage.RewardPDVSource, Timestamp: today}))
+	require.NoError(t, s.RefreshViews(ctx))
+
+	require.Equal(t, []*storage.LeaderboardItem{
+		{Address: "1", Value: 10, Rank: 2},
+	}, list(storage.ListLeaderboardParams{Metric: storage.PDVLeaderboardMetric, Period: storage.DayLeaderboardPeriod, Limit: 1, After: after}))
+}
+
 func TestPg_WipeAccount_InTx(t *testing.T) {
 	defer cleanup(t)
 
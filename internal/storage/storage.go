@@ -17,17 +17,34 @@ var ErrNotFound = fmt.Errorf("not found")
 // PDVDenominator is used to guarantee precision for storing pdv with int64.
 const PDVDenominator = 1000000
 
+// PDVDenom is a denom of pdv token balance. The balance is stored in uPDV above the initial one.
+const PDVDenom = "updv"
+
 // Storage provides methods for interacting with database.
 type Storage interface {
 	InTx(ctx context.Context, f func(s Storage) error) error
 	SetHeight(ctx context.Context, height uint64) error
 	GetHeight(ctx context.Context) (uint64, error)
-	RefreshViews(ctx context.Context, postView, statsView bool) error
 
-	Follow(ctx context.Context, follower, followee string) error
-	Unfollow(ctx context.Context, follower, followee string) error
+	AddMessage(ctx context.Context, m *Message) error
+	ListMessages(ctx context.Context, from, to uint64) ([]*Message, error)
+	AddJournalRange(ctx context.Context, from, to uint64) error
+	ListJournalRanges(ctx context.Context) ([]*HeightRange, error)
+	TruncateDerived(ctx context.Context) error
+
+	Follow(ctx context.Context, follower, followee string, height uint64, timestamp time.Time) error
+	Unfollow(ctx context.Context, follower, followee string, height uint64, timestamp time.Time) error
+	GetFollowersStats(ctx context.Context, address string) (FollowersStats, error)
+	ListFollowers(ctx context.Context, p *ListFollowsParams) ([]*Follow, error)
+	ListFollowing(ctx context.Context, p *ListFollowsParams) ([]*Follow, error)
+	GetFollowsCount(ctx context.Context, address string) (*FollowsCount, error)
+	GetFollowed(ctx context.Context, follower string, followee ...string) (map[string]bool, error)
+	ListFollowSuggestions(ctx context.Context, address string, limit uint16) ([]*FollowSuggestion, error)
 
 	ListPosts(ctx context.Context, p *ListPostsParams) ([]*Post, error)
+	SearchPosts(ctx context.Context, p *SearchPostsParams) ([]*SearchResult, error)
+	ListFeed(ctx context.Context, p *ListFeedParams) ([]*Post, error)
+	ListRelatedPosts(ctx context.Context, id PostID, limit uint16) ([]*Post, error)
 	CreatePost(ctx context.Context, p *CreatePostParams) error
 	GetPost(ctx context.Context, id PostID) (*Post, error)
 	GetPostBySlug(ctx context.Context, slug string) (*Post, error)
@@ -35,14 +52,27 @@ type Storage interface {
 
 	GetLikes(ctx context.Context, likedBy string, id ...PostID) (map[PostID]community.LikeWeight, error)
 	SetLike(ctx context.Context, id PostID, weight community.LikeWeight, timestamp time.Time, likeOwner string) error
+	ListLikes(ctx context.Context, p *ListLikesParams) ([]*Like, error)
+
+	AddPDV(ctx context.Context, p *PDV) error
+	ListPDV(ctx context.Context, p *ListPDVParams) ([]*PDV, error)
 
-	AddPDV(ctx context.Context, address string, amount int64, timestamp time.Time) error
+	AddTokenMovement(ctx context.Context, m *TokenMovement) error
+	ListTokenMovements(ctx context.Context, p *ListTokenMovementsParams) ([]*TokenMovement, error)
+	GetSupplyStats(ctx context.Context, denom string) ([]*SupplyStatsItem, error)
+
+	GetBalances(ctx context.Context, address string) ([]*Balance, error)
 
 	GetProfileStats(ctx context.Context, addr ...string) ([]*ProfileStats, error)
 	GetPostStats(ctx context.Context, id ...PostID) (map[PostID]PostStats, error)
 
+	RefreshViews(ctx context.Context) error
+	RefreshRelatedPosts(ctx context.Context) error
 	GetDecentrStats(ctx context.Context) (*DecentrStats, error)
 	GetDDVStats(ctx context.Context) ([]*DDVStatsItem, error)
+	GetCategoryStats(ctx context.Context) ([]*CategoryStats, error)
+	ListLeaderboard(ctx context.Context, p *ListLeaderboardParams) ([]*LeaderboardItem, error)
+	GetLeaderboardItem(ctx context.Context, metric LeaderboardMetric, period LeaderboardPeriod, address string) (*LeaderboardItem, error)
 
 	ResetAccount(ctx context.Context, owner string) error
 }
@@ -59,6 +89,15 @@ const (
 	DislikesSortType SortType = "dislikes"
 	// PDVSortType ...
 	PDVSortType SortType = "updv"
+	// HotSortType sorts by net likes decayed by post age.
+	HotSortType SortType = "hot"
+	// RisingSortType sorts by net likes received during the last day decayed by post age.
+	// Only posts with positive net likes during the last day are listed.
+	RisingSortType SortType = "rising"
+	// BestSortType sorts by lower bound of Wilson score interval for a share of likes.
+	BestSortType SortType = "best"
+	// ControversialSortType sorts by votes count weighted by balance of likes and dislikes.
+	ControversialSortType SortType = "controversial"
 )
 
 // OrderType ...
@@ -82,9 +121,216 @@ type ListPostsParams struct {
 	Owner           *string
 	LikedBy         *string
 	FollowedBy      *string
-	After           *PostID
-	From            *uint64
-	To              *uint64
+	// Query is a full-text search query in websearch format.
+	Query *string
+	After *PostsCursor
+	From  *uint64
+	To    *uint64
+	// Now is a reference time of rising sort, the current time is used when it's zero.
+	// It should be kept between pages, otherwise scores of pages are not comparable.
+	Now time.Time
+}
+
+// Follow ...
+type Follow struct {
+	Follower string
+	Followee string
+	Height   uint64
+	// CreatedAt is zero for follows made before the time was tracked.
+	CreatedAt time.Time
+}
+
+// ListFollowsParams ...
+type ListFollowsParams struct {
+	Address string
+	Limit   uint16
+	// After is a position of the follow listed last on the previous page.
+	After *FollowsCursor
+}
+
+// FollowsCursor is a position in follows list ordered by creation time.
+type FollowsCursor struct {
+	CreatedAt time.Time
+	// Address is a listed address used as a tie-breaker.
+	Address string
+}
+
+// FollowsCount ...
+type FollowsCount struct {
+	Followers uint32
+	Following uint32
+}
+
+// FollowSuggestion is an address suggested to follow.
+type FollowSuggestion struct {
+	Address string
+	// FollowedByFollowees is a count of the address' followees who follow the suggested address.
+	FollowedByFollowees uint32
+	// LikedPosts is a count of the suggested address' posts liked by the address.
+	LikedPosts uint32
+}
+
+// Like is a last like of the post made by the address.
+type Like struct {
+	Post    PostID
+	LikedBy string
+	Weight  community.LikeWeight
+	LikedAt time.Time
+}
+
+// ListLikesParams ...
+type ListLikesParams struct {
+	Post   PostID
+	Weight *community.LikeWeight
+	Limit  uint16
+	// After is a position of the like listed last on the previous page.
+	After *LikesCursor
+}
+
+// LikesCursor is a position in likes list ordered by like time.
+type LikesCursor struct {
+	LikedAt time.Time
+	// LikedBy is used as a tie-breaker.
+	LikedBy string
+}
+
+// PostsCursor is a position in posts list, it contains sort key values of the last listed post.
+type PostsCursor struct {
+	// Value is a value of sort field, created_at is represented in unix microseconds.
+	Value int64
+	// Score is a value of score sort field (hot, rising, best, controversial), Value is not used with them.
+	Score float64
+	Owner string
+	UUID  string
+}
+
+// SearchPostsParams ...
+type SearchPostsParams struct {
+	// Query is a full-text search query in websearch format.
+	Query    string
+	Category *community.Category
+	Limit    uint16
+	After    *SearchCursor
+}
+
+// SearchCursor is a position in search results ordered by rank.
+type SearchCursor struct {
+	Rank float64
+	// Owner and UUID are used as a tie-breaker.
+	Owner string
+	UUID  string
+}
+
+// SearchResult is a post found by full-text search.
+type SearchResult struct {
+	Post *Post
+	Rank float64
+	// Snippet is a fragment of post text with matched words wrapped in <b> tag.
+	Snippet string
+}
+
+// ListFeedParams ...
+// Feed contains posts of followees, posts liked by followees and the hottest posts with positive net likes
+// in categories the address likes and posts the most. Posts are sorted by hot score descending.
+type ListFeedParams struct {
+	Address string
+	Limit   uint16
+	After   *PostsCursor
+}
+
+// PDVSource ...
+type PDVSource string
+
+const (
+	// UnknownPDVSource is a source of pdv saved before sources were tracked.
+	UnknownPDVSource PDVSource = "unknown"
+	// GenesisPDVSource ...
+	GenesisPDVSource PDVSource = "genesis"
+	// RewardPDVSource ...
+	RewardPDVSource PDVSource = "reward"
+	// LikePDVSource ...
+	LikePDVSource PDVSource = "like"
+)
+
+// PDV is a change of address pdv in uPDV.
+type PDV struct {
+	ID      uint64
+	Address string
+	Amount  int64
+	Source  PDVSource
+	// Post is a liked post, it's set for like source only.
+	Post *PostID
+	// Counterparty is a liker or a rewards distributor.
+	Counterparty string
+	Timestamp    time.Time
+}
+
+// ListPDVParams ...
+type ListPDVParams struct {
+	Address string
+	Limit   uint16
+	After   *uint64
+}
+
+// TokenMovementType ...
+type TokenMovementType string
+
+const (
+	// MintTokenMovementType ...
+	MintTokenMovementType TokenMovementType = "mint"
+	// BurnTokenMovementType ...
+	BurnTokenMovementType TokenMovementType = "burn"
+)
+
+// TokenMovement is a mint or a burn of tokens by operations module.
+type TokenMovement struct {
+	ID        uint64
+	Type      TokenMovementType
+	Address   string
+	Denom     string
+	Amount    int64
+	Timestamp time.Time
+}
+
+// ListTokenMovementsParams ...
+type ListTokenMovementsParams struct {
+	Denom   *string
+	Address *string
+	Limit   uint16
+	After   *uint64
+}
+
+// SupplyStatsItem contains amounts of tokens minted and burned during the date.
+type SupplyStatsItem struct {
+	Date   time.Time
+	Minted int64
+	Burned int64
+}
+
+// Balance is an address balance of the denom.
+type Balance struct {
+	Denom   string
+	Amount  int64
+	History BalanceHistory
+}
+
+// BalanceHistory is a map where key is date in RFC3339 format and value is balance at the end of the date.
+type BalanceHistory map[string]int64
+
+// Message is a blockchain message saved to the messages journal.
+type Message struct {
+	Height   uint64
+	Time     time.Time
+	TxIndex  uint32
+	MsgIndex uint32
+	Type     string
+	Payload  []byte
+}
+
+// HeightRange is an inclusive range of block heights.
+type HeightRange struct {
+	From uint64
+	To   uint64
 }
 
 // PostID ...
@@ -117,13 +363,20 @@ type Post struct {
 	Dislikes     uint32
 	UPDV         int64
 	Slug         string
+	// Score is a value of hot, rising, best or controversial score, it's set when posts are listed sorted by them.
+	Score float64
 }
 
 // ProfileStats ...
 type ProfileStats struct {
-	Address    string
-	PostsCount uint16
-	Stats      PostStats
+	Address        string
+	PostsCount     uint16
+	FollowersCount uint32
+	FollowingCount uint32
+	// LikesCount and DislikesCount are received by profile's posts.
+	LikesCount    uint32
+	DislikesCount uint32
+	Stats         PostStats
 }
 
 // DecentrStats represents all users stats.
@@ -138,5 +391,71 @@ type DDVStatsItem struct {
 	Value int64     `json:"value"`
 }
 
+// CategoryStats ...
+type CategoryStats struct {
+	Category   community.Category
+	PostsCount uint32
+	// ActiveAuthors is a count of authors posted in the category during the last 30 days.
+	ActiveAuthors uint32
+	// LikesCount is received by category's posts.
+	LikesCount uint32
+	// Stats contains count of posts created by date during the last 90 days.
+	Stats PostStats
+}
+
+// LeaderboardMetric ...
+type LeaderboardMetric string
+
+const (
+	// PDVLeaderboardMetric ranks addresses by earned updv.
+	PDVLeaderboardMetric LeaderboardMetric = "pdv"
+	// LikesLeaderboardMetric ranks addresses by received likes.
+	LikesLeaderboardMetric LeaderboardMetric = "likes"
+	// PostsLeaderboardMetric ranks addresses by created posts.
+	PostsLeaderboardMetric LeaderboardMetric = "posts"
+)
+
+// LeaderboardPeriod is a period ending today values are ranked over.
+type LeaderboardPeriod string
+
+const (
+	// DayLeaderboardPeriod ...
+	DayLeaderboardPeriod LeaderboardPeriod = "day"
+	// WeekLeaderboardPeriod ...
+	WeekLeaderboardPeriod LeaderboardPeriod = "week"
+	// MonthLeaderboardPeriod is a period of 30 days.
+	MonthLeaderboardPeriod LeaderboardPeriod = "month"
+	// AllLeaderboardPeriod ...
+	AllLeaderboardPeriod LeaderboardPeriod = "all"
+)
+
+// ListLeaderboardParams ...
+type ListLeaderboardParams struct {
+	Metric LeaderboardMetric
+	Period LeaderboardPeriod
+	Limit  uint16
+	// After is a position of the item listed last on the previous page.
+	After *LeaderboardCursor
+}
+
+// LeaderboardCursor is a position in leaderboard ordered by value descending.
+// Ranks are not used, because they are recomputed by every refresh.
+type LeaderboardCursor struct {
+	Value int64
+	// Address is used as a tie-breaker.
+	Address string
+}
+
+// LeaderboardItem ...
+type LeaderboardItem struct {
+	Address string
+	Value   int64
+	// Rank starts from 1, addresses with equal values have equal ranks.
+	Rank uint64
+}
+
+// FollowersStats is a map where key is date in RFC3339 format and value is followers count at the end of the date.
+type FollowersStats map[string]int64
+
 // PostStats is a map where key is date in RFC3339 format and value is uPDV count.
 type PostStats map[string]int64
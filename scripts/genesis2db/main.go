@@ -11,7 +11,6 @@ import (
 
 	communitytypes "github.com/Decentr-net/decentr/x/community/types"
 	tokentypes "github.com/Decentr-net/decentr/x/token/types"
-	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	"github.com/golang-migrate/migrate/v4"
 	migratep "github.com/golang-migrate/migrate/v4/database/postgres"
@@ -20,7 +19,7 @@ import (
 	_ "github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 
-	_ "github.com/Decentr-net/theseus/internal/consumer/blockchain"
+	"github.com/Decentr-net/theseus/internal/consumer/blockchain"
 	"github.com/Decentr-net/theseus/internal/storage"
 	"github.com/Decentr-net/theseus/internal/storage/postgres"
 )
@@ -72,70 +71,14 @@ func main() {
 
 	t := time.Now().UTC()
 
-	logrus.Info("import token")
-	i := 0
-	for k, v := range g.AppState.Token.Balances {
-		if err := s.AddPDV(context.Background(), k, v.Dec.Sub(sdk.OneDec()).TruncateInt64()*storage.PDVDenominator, t); err != nil {
-			logrus.WithError(err).Fatal("failed to put token into db")
-		}
-
-		i++
-		if i%20 == 0 {
-			logrus.Infof("%d of %d balances imported", i+1, len(g.AppState.Token.Balances))
-		}
-	}
-
-	i = 0
-	logrus.Info("import followings")
-	for follower, v := range g.AppState.Community.Following {
-		for _, followee := range v.Address {
-			if err := s.Follow(context.Background(), follower, followee); err != nil {
-				logrus.WithError(err).Fatal("failed to put following into db")
-			}
-		}
-
-		i++
-		if i%20 == 0 {
-			logrus.Infof("%d of %d followers imported", i+1, len(g.AppState.Community.Following))
-		}
-	}
-
-	logrus.Info("import posts")
-	for i, v := range g.AppState.Community.Posts {
-		if err := s.CreatePost(context.Background(), &storage.CreatePostParams{
-			UUID:         v.Uuid,
-			Owner:        v.Owner,
-			Title:        v.Title,
-			Category:     v.Category,
-			PreviewImage: v.PreviewImage,
-			Text:         v.Text,
-			CreatedAt:    t,
-		}); err != nil {
-			logrus.WithError(err).Fatal("failed to put post into db")
-		}
-
-		if i%20 == 0 {
-			logrus.Infof("%d of %d posts imported", i+1, len(g.AppState.Community.Posts))
-		}
-	}
-
-	logrus.Info("import likes")
-	for i, v := range g.AppState.Community.Likes {
-		if err := s.SetLike(context.Background(), storage.PostID{
-			Owner: v.PostOwner,
-			UUID:  v.PostUuid,
-		}, v.Weight, t, v.Owner); err != nil {
-			logrus.WithError(err).Fatal("failed to put like into db")
-		}
-
-		if i%20 == 0 {
-			logrus.Infof("%d of %d likes imported", i+1, len(g.AppState.Community.Likes))
-		}
+	if err := s.InTx(context.Background(), func(s storage.Storage) error {
+		return blockchain.ImportGenesis(context.Background(), s, t, &g.AppState.Token, &g.AppState.Community)
+	}); err != nil {
+		logrus.WithError(err).Fatal("failed to import genesis")
 	}
 
-	logrus.Info("refreshing posts view")
-	if _, err := db.Exec(`REFRESH MATERIALIZED VIEW calculated_post`); err != nil {
-		logrus.WithError(err).Fatal("failed to refresh posts view")
+	if err := s.RefreshViews(context.Background()); err != nil {
+		logrus.WithError(err).Fatal("failed to refresh views")
 	}
 
 	logrus.Info("done")
@@ -0,0 +1,14 @@
+BEGIN;
+
+CREATE TABLE message (
+    height BIGINT NOT NULL,
+    tx_index INT NOT NULL,
+    msg_index INT NOT NULL,
+    type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    block_time TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+
+    PRIMARY KEY (height, tx_index, msg_index)
+);
+
+COMMIT;
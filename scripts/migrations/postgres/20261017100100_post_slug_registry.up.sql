@@ -0,0 +1,54 @@
+BEGIN;
+
+-- post_slug keeps issued slugs, so posts get the same slugs when derived tables are rebuilt
+CREATE TABLE post_slug (
+    owner TEXT NOT NULL,
+    uuid TEXT NOT NULL,
+    slug TEXT NOT NULL UNIQUE,
+
+    PRIMARY KEY (owner, uuid)
+);
+
+INSERT INTO post_slug(owner, uuid, slug)
+SELECT owner, uuid, slug FROM post;
+
+CREATE OR REPLACE FUNCTION unique_post_slug()
+    RETURNS TRIGGER AS
+$$
+
+DECLARE
+    key TEXT;
+BEGIN
+
+    -- Reuse the slug issued before for the same post.
+    SELECT slug INTO key FROM post_slug WHERE owner = NEW.owner AND uuid = NEW.uuid;
+
+    IF key IS NOT NULL THEN
+        NEW.slug = key;
+        RETURN NEW;
+    END IF;
+
+    -- This loop will probably only run once per call until we've generated
+    -- millions of ids.
+    LOOP
+
+        -- Generate our string bytes and re-encode as a base64 string.
+        key := encode(gen_random_bytes(6), 'base64');
+
+        -- Base64 encoding contains 2 URL unsafe characters by default.
+        -- The URL-safe version has these replacements.
+        key := replace(key, '/', '_'); -- url safe replacement
+        key := replace(key, '+', '-'); -- url safe replacement
+
+        EXIT WHEN NOT EXISTS(SELECT 1 FROM post_slug WHERE slug = key);
+    END LOOP;
+
+    INSERT INTO post_slug(owner, uuid, slug) VALUES (NEW.owner, NEW.uuid, key);
+
+    NEW.slug = key;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE 'plpgsql';
+
+COMMIT;
@@ -0,0 +1,70 @@
+BEGIN;
+
+DROP VIEW calculated_post;
+
+DROP INDEX post_created_at_idx;
+DROP INDEX post_likes_idx;
+DROP INDEX post_category_idx;
+
+DROP TRIGGER trigger_pdv_counters ON updv;
+DROP FUNCTION pdv_counters();
+
+DROP TRIGGER trigger_like_counters ON "like";
+DROP FUNCTION like_counters();
+
+DROP TABLE pdv_stats_daily;
+DROP TABLE post_stats_daily;
+
+ALTER TABLE post
+    DROP COLUMN likes,
+    DROP COLUMN dislikes,
+    DROP COLUMN updv;
+
+CREATE MATERIALIZED VIEW stats AS
+    WITH pre AS (
+        SELECT post_owner as owner, post_uuid as uuid, liked_at::DATE as date, SUM(weight) as updv
+        from "like"
+        group by owner, uuid, date
+    ),
+         r AS (
+             SELECT owner, uuid, date, SUM(updv) OVER (PARTITION BY (owner, uuid) ORDER BY date) as updv
+             from pre
+         )
+    SELECT owner, uuid, json_object_agg(date, updv) AS stats FROM r
+    GROUP BY owner, uuid;
+
+CREATE UNIQUE INDEX stats_pk_idx ON stats(owner, uuid);
+
+CREATE MATERIALIZED VIEW pdv_stats AS
+    WITH pre AS (
+        SELECT address, timestamp::DATE as date, SUM(updv) as updv
+        from updv
+        group by address, date
+    ),
+     r AS (
+         SELECT address, date, SUM(updv) OVER (PARTITION BY (address) ORDER BY date) as updv
+         from pre
+     )
+SELECT address, json_object_agg(date, updv) AS stats FROM r
+GROUP BY address;
+
+CREATE UNIQUE INDEX pdv_stats_pk_idx ON pdv_stats(address);
+
+CREATE MATERIALIZED VIEW calculated_post AS
+SELECT owner, uuid, title, category, preview_image, text, post.created_at,
+       COALESCE(COUNT(weight) FILTER (WHERE weight = 1), 0) as likes,
+       COALESCE(COUNT(weight) FILTER (WHERE weight = -1), 0) AS dislikes,
+       COALESCE(SUM(weight), 0) AS updv,
+       slug
+FROM post
+         LEFT JOIN "like" ON post.owner = "like".post_owner AND post.uuid = "like".post_uuid
+WHERE deleted_at IS NULL
+GROUP BY owner, uuid, title, category, preview_image, text, post.created_at;
+
+CREATE UNIQUE INDEX calculated_post_pk_idx ON calculated_post(owner, uuid);
+CREATE INDEX calculated_post_created_at_idx ON calculated_post(created_at DESC);
+CREATE INDEX calculated_post_likes_idx ON calculated_post(likes DESC);
+CREATE INDEX calculated_post_category_idx ON calculated_post(category);
+CREATE INDEX calculated_post_slug_idx ON calculated_post(slug);
+
+COMMIT;
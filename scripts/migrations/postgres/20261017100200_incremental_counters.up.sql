@@ -0,0 +1,126 @@
+BEGIN;
+
+DROP MATERIALIZED VIEW calculated_post;
+DROP MATERIALIZED VIEW stats;
+DROP MATERIALIZED VIEW pdv_stats;
+
+-- post counters are maintained by like_counters trigger
+ALTER TABLE post
+    ADD COLUMN likes INT NOT NULL DEFAULT 0,
+    ADD COLUMN dislikes INT NOT NULL DEFAULT 0,
+    ADD COLUMN updv BIGINT NOT NULL DEFAULT 0;
+
+UPDATE post SET likes = l.likes, dislikes = l.dislikes, updv = l.updv
+FROM (
+    SELECT post_owner, post_uuid,
+           COUNT(*) FILTER (WHERE weight = 1) AS likes,
+           COUNT(*) FILTER (WHERE weight = -1) AS dislikes,
+           SUM(weight) AS updv
+    FROM "like"
+    GROUP BY post_owner, post_uuid
+) l
+WHERE post.owner = l.post_owner AND post.uuid = l.post_uuid;
+
+-- post_stats_daily contains sum of likes weights by the date of like
+CREATE TABLE post_stats_daily (
+    owner TEXT NOT NULL,
+    uuid TEXT NOT NULL,
+    date DATE NOT NULL,
+    updv BIGINT NOT NULL,
+
+    PRIMARY KEY (owner, uuid, date)
+);
+
+INSERT INTO post_stats_daily(owner, uuid, date, updv)
+SELECT post_owner, post_uuid, liked_at::DATE, SUM(weight)
+FROM "like"
+GROUP BY post_owner, post_uuid, liked_at::DATE;
+
+-- pdv_stats_daily contains sum of earned updv by date
+CREATE TABLE pdv_stats_daily (
+    address TEXT NOT NULL,
+    date DATE NOT NULL,
+    updv BIGINT NOT NULL,
+
+    PRIMARY KEY (address, date)
+);
+
+INSERT INTO pdv_stats_daily(address, date, updv)
+SELECT address, timestamp::DATE, SUM(updv)
+FROM updv
+GROUP BY address, timestamp::DATE;
+
+CREATE OR REPLACE FUNCTION like_counters()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF (TG_OP = 'UPDATE' OR TG_OP = 'DELETE') THEN
+        UPDATE post SET
+            likes = likes - (OLD.weight = 1)::INT,
+            dislikes = dislikes - (OLD.weight = -1)::INT,
+            updv = updv - OLD.weight
+        WHERE owner = OLD.post_owner AND uuid = OLD.post_uuid;
+
+        INSERT INTO post_stats_daily(owner, uuid, date, updv)
+        VALUES (OLD.post_owner, OLD.post_uuid, OLD.liked_at::DATE, -OLD.weight)
+        ON CONFLICT (owner, uuid, date) DO UPDATE SET updv = post_stats_daily.updv + excluded.updv;
+    END IF;
+
+    IF (TG_OP = 'INSERT' OR TG_OP = 'UPDATE') THEN
+        UPDATE post SET
+            likes = likes + (NEW.weight = 1)::INT,
+            dislikes = dislikes + (NEW.weight = -1)::INT,
+            updv = updv + NEW.weight
+        WHERE owner = NEW.post_owner AND uuid = NEW.post_uuid;
+
+        INSERT INTO post_stats_daily(owner, uuid, date, updv)
+        VALUES (NEW.post_owner, NEW.post_uuid, NEW.liked_at::DATE, NEW.weight)
+        ON CONFLICT (owner, uuid, date) DO UPDATE SET updv = post_stats_daily.updv + excluded.updv;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE 'plpgsql';
+
+CREATE TRIGGER trigger_like_counters
+    AFTER INSERT OR UPDATE OR DELETE
+    ON "like"
+    FOR EACH ROW
+EXECUTE PROCEDURE like_counters();
+
+CREATE OR REPLACE FUNCTION pdv_counters()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF (TG_OP = 'DELETE') THEN
+        UPDATE pdv_stats_daily SET updv = updv - OLD.updv
+        WHERE address = OLD.address AND date = OLD.timestamp::DATE;
+
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO pdv_stats_daily(address, date, updv)
+    VALUES (NEW.address, NEW.timestamp::DATE, NEW.updv)
+    ON CONFLICT (address, date) DO UPDATE SET updv = pdv_stats_daily.updv + excluded.updv;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE 'plpgsql';
+
+CREATE TRIGGER trigger_pdv_counters
+    AFTER INSERT OR DELETE
+    ON updv
+    FOR EACH ROW
+EXECUTE PROCEDURE pdv_counters();
+
+-- calculated_post is kept as a plain view to not change queries
+CREATE VIEW calculated_post AS
+SELECT owner, uuid, title, category, preview_image, text, created_at, likes, dislikes, updv, slug
+FROM post
+WHERE deleted_at IS NULL;
+
+CREATE INDEX post_created_at_idx ON post(created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX post_likes_idx ON post(likes DESC) WHERE deleted_at IS NULL;
+CREATE INDEX post_category_idx ON post(category) WHERE deleted_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP MATERIALIZED VIEW ddv_stats;
+DROP MATERIALIZED VIEW decentr_stats;
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+-- views are refreshed concurrently in background, so unique indexes are required
+CREATE MATERIALIZED VIEW decentr_stats AS
+WITH pdv AS (
+    SELECT address, SUM(updv) AS earned_pdv FROM pdv_stats_daily
+    GROUP BY address
+)
+SELECT 1 AS id, SUM(earned_pdv) AS ddv, AVG(earned_pdv) AS avg_earned_pdv FROM pdv;
+
+CREATE UNIQUE INDEX decentr_stats_id_idx ON decentr_stats(id);
+
+CREATE MATERIALIZED VIEW ddv_stats AS
+SELECT date, SUM(updv) AS value FROM pdv_stats_daily
+GROUP BY date;
+
+CREATE UNIQUE INDEX ddv_stats_date_idx ON ddv_stats(date);
+
+COMMIT;
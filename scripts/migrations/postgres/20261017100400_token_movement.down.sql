@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE token_movement;
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE TABLE token_movement (
+    id BIGSERIAL PRIMARY KEY,
+    type TEXT NOT NULL CHECK (type IN ('mint', 'burn')),
+    address TEXT NOT NULL,
+    denom TEXT NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount >= 0),
+    timestamp TIMESTAMP NOT NULL
+);
+
+CREATE INDEX token_movement_denom_idx ON token_movement(denom, id);
+CREATE INDEX token_movement_address_idx ON token_movement(address, id);
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+DROP TRIGGER trigger_token_movement_balance ON token_movement;
+DROP FUNCTION token_movement_balance;
+DROP TRIGGER trigger_pdv_balance ON updv;
+DROP FUNCTION pdv_balance;
+DROP FUNCTION add_balance;
+
+DROP TABLE balance_daily;
+DROP TABLE balance;
+
+COMMIT;
@@ -0,0 +1,95 @@
+BEGIN;
+
+-- balance contains amounts of tokens by address and denom.
+-- updv denom is a pdv token balance above the initial one, it's maintained by pdv_balance trigger,
+-- other denoms are maintained by token_movement_balance trigger.
+CREATE TABLE balance (
+    address TEXT NOT NULL,
+    denom TEXT NOT NULL,
+    amount BIGINT NOT NULL,
+
+    PRIMARY KEY (address, denom)
+);
+
+-- balance_daily contains balance changes by date
+CREATE TABLE balance_daily (
+    address TEXT NOT NULL,
+    denom TEXT NOT NULL,
+    date DATE NOT NULL,
+    amount BIGINT NOT NULL,
+
+    PRIMARY KEY (address, denom, date)
+);
+
+CREATE OR REPLACE FUNCTION add_balance(_address TEXT, _denom TEXT, _date DATE, _amount BIGINT)
+    RETURNS VOID AS
+$$
+BEGIN
+    INSERT INTO balance(address, denom, amount)
+    VALUES (_address, _denom, _amount)
+    ON CONFLICT (address, denom) DO UPDATE SET amount = balance.amount + excluded.amount;
+
+    INSERT INTO balance_daily(address, denom, date, amount)
+    VALUES (_address, _denom, _date, _amount)
+    ON CONFLICT (address, denom, date) DO UPDATE SET amount = balance_daily.amount + excluded.amount;
+END;
+$$ LANGUAGE 'plpgsql';
+
+CREATE OR REPLACE FUNCTION pdv_balance()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF (TG_OP = 'DELETE') THEN
+        PERFORM add_balance(OLD.address, 'updv', OLD.timestamp::DATE, -OLD.updv);
+    ELSE
+        PERFORM add_balance(NEW.address, 'updv', NEW.timestamp::DATE, NEW.updv);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE 'plpgsql';
+
+CREATE TRIGGER trigger_pdv_balance
+    AFTER INSERT OR DELETE
+    ON updv
+    FOR EACH ROW
+EXECUTE PROCEDURE pdv_balance();
+
+CREATE OR REPLACE FUNCTION token_movement_balance()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF (TG_OP = 'DELETE') THEN
+        PERFORM add_balance(OLD.address, OLD.denom, OLD.timestamp::DATE,
+            CASE WHEN OLD.type = 'mint' THEN -OLD.amount ELSE OLD.amount END);
+    ELSE
+        PERFORM add_balance(NEW.address, NEW.denom, NEW.timestamp::DATE,
+            CASE WHEN NEW.type = 'mint' THEN NEW.amount ELSE -NEW.amount END);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE 'plpgsql';
+
+CREATE TRIGGER trigger_token_movement_balance
+    AFTER INSERT OR DELETE
+    ON token_movement
+    FOR EACH ROW
+EXECUTE PROCEDURE token_movement_balance();
+
+INSERT INTO balance_daily(address, denom, date, amount)
+SELECT address, 'updv', timestamp::DATE, SUM(updv)
+FROM updv
+GROUP BY address, timestamp::DATE;
+
+INSERT INTO balance_daily(address, denom, date, amount)
+SELECT address, denom, timestamp::DATE, SUM(CASE WHEN type = 'mint' THEN amount ELSE -amount END)
+FROM token_movement
+GROUP BY address, denom, timestamp::DATE;
+
+INSERT INTO balance(address, denom, amount)
+SELECT address, denom, SUM(amount)
+FROM balance_daily
+GROUP BY address, denom;
+
+COMMIT;
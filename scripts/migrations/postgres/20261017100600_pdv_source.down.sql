@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP INDEX updv_address_id_idx;
+
+ALTER TABLE updv
+    DROP COLUMN source,
+    DROP COLUMN post_owner,
+    DROP COLUMN post_uuid,
+    DROP COLUMN counterparty;
+
+COMMIT;
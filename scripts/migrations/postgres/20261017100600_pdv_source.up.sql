@@ -0,0 +1,15 @@
+BEGIN;
+
+-- source of rows inserted before is unknown, they get it on syncd rebuild
+ALTER TABLE updv
+    ADD COLUMN source TEXT NOT NULL DEFAULT 'unknown'
+        CHECK (source IN ('unknown', 'genesis', 'reward', 'like')),
+    ADD COLUMN post_owner TEXT,
+    ADD COLUMN post_uuid TEXT,
+    ADD COLUMN counterparty TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE updv ALTER COLUMN source DROP DEFAULT;
+
+CREATE INDEX updv_address_id_idx ON updv(address, id DESC);
+
+COMMIT;
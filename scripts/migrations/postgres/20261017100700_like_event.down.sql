@@ -0,0 +1,48 @@
+BEGIN;
+
+DROP TRIGGER trigger_like_event_stats ON like_event;
+DROP FUNCTION like_event_stats;
+
+CREATE OR REPLACE FUNCTION like_counters()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF (TG_OP = 'UPDATE' OR TG_OP = 'DELETE') THEN
+        UPDATE post SET
+            likes = likes - (OLD.weight = 1)::INT,
+            dislikes = dislikes - (OLD.weight = -1)::INT,
+            updv = updv - OLD.weight
+        WHERE owner = OLD.post_owner AND uuid = OLD.post_uuid;
+
+        INSERT INTO post_stats_daily(owner, uuid, date, updv)
+        VALUES (OLD.post_owner, OLD.post_uuid, OLD.liked_at::DATE, -OLD.weight)
+        ON CONFLICT (owner, uuid, date) DO UPDATE SET updv = post_stats_daily.updv + excluded.updv;
+    END IF;
+
+    IF (TG_OP = 'INSERT' OR TG_OP = 'UPDATE') THEN
+        UPDATE post SET
+            likes = likes + (NEW.weight = 1)::INT,
+            dislikes = dislikes + (NEW.weight = -1)::INT,
+            updv = updv + NEW.weight
+        WHERE owner = NEW.post_owner AND uuid = NEW.post_uuid;
+
+        INSERT INTO post_stats_daily(owner, uuid, date, updv)
+        VALUES (NEW.post_owner, NEW.post_uuid, NEW.liked_at::DATE, NEW.weight)
+        ON CONFLICT (owner, uuid, date) DO UPDATE SET updv = post_stats_daily.updv + excluded.updv;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE 'plpgsql';
+
+-- post_stats_daily is consistent with the last likes again
+DELETE FROM post_stats_daily;
+
+INSERT INTO post_stats_daily(owner, uuid, date, updv)
+SELECT post_owner, post_uuid, liked_at::DATE, SUM(weight)
+FROM "like"
+GROUP BY post_owner, post_uuid, liked_at::DATE;
+
+DROP TABLE like_event;
+
+COMMIT;
@@ -0,0 +1,73 @@
+BEGIN;
+
+-- like_event is an append-only history of likes, like table contains only the last weight
+CREATE TABLE like_event (
+    id BIGSERIAL PRIMARY KEY,
+    post_owner TEXT NOT NULL,
+    post_uuid TEXT NOT NULL,
+    liked_by TEXT NOT NULL,
+    weight SMALLINT NOT NULL,
+    delta SMALLINT NOT NULL,
+    timestamp TIMESTAMP NOT NULL
+);
+
+CREATE INDEX like_event_post_idx ON like_event(post_owner, post_uuid);
+CREATE INDEX like_event_liked_by_idx ON like_event(liked_by);
+
+-- the real history is unknown, it's restored by syncd rebuild
+INSERT INTO like_event(post_owner, post_uuid, liked_by, weight, delta, timestamp)
+SELECT post_owner, post_uuid, liked_by, weight, weight, liked_at
+FROM "like"
+ORDER BY liked_at;
+
+-- post_stats_daily is maintained by like events now
+CREATE OR REPLACE FUNCTION like_counters()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF (TG_OP = 'UPDATE' OR TG_OP = 'DELETE') THEN
+        UPDATE post SET
+            likes = likes - (OLD.weight = 1)::INT,
+            dislikes = dislikes - (OLD.weight = -1)::INT,
+            updv = updv - OLD.weight
+        WHERE owner = OLD.post_owner AND uuid = OLD.post_uuid;
+    END IF;
+
+    IF (TG_OP = 'INSERT' OR TG_OP = 'UPDATE') THEN
+        UPDATE post SET
+            likes = likes + (NEW.weight = 1)::INT,
+            dislikes = dislikes + (NEW.weight = -1)::INT,
+            updv = updv + NEW.weight
+        WHERE owner = NEW.post_owner AND uuid = NEW.post_uuid;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE 'plpgsql';
+
+CREATE OR REPLACE FUNCTION like_event_stats()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF (TG_OP = 'DELETE') THEN
+        UPDATE post_stats_daily SET updv = updv - OLD.delta
+        WHERE owner = OLD.post_owner AND uuid = OLD.post_uuid AND date = OLD.timestamp::DATE;
+
+        RETURN NULL;
+    END IF;
+
+    INSERT INTO post_stats_daily(owner, uuid, date, updv)
+    VALUES (NEW.post_owner, NEW.post_uuid, NEW.timestamp::DATE, NEW.delta)
+    ON CONFLICT (owner, uuid, date) DO UPDATE SET updv = post_stats_daily.updv + excluded.updv;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE 'plpgsql';
+
+CREATE TRIGGER trigger_like_event_stats
+    AFTER INSERT OR DELETE
+    ON like_event
+    FOR EACH ROW
+EXECUTE PROCEDURE like_event_stats();
+
+COMMIT;
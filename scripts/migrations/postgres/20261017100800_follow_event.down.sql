@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE follow_event;
+
+ALTER TABLE follow
+    DROP COLUMN height,
+    DROP COLUMN created_at;
+
+COMMIT;
@@ -0,0 +1,29 @@
+BEGIN;
+
+-- time of follows made before is unknown, zero time is used as it is for genesis stats
+ALTER TABLE follow
+    ADD COLUMN height BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN created_at TIMESTAMP NOT NULL DEFAULT '0001-01-01';
+
+ALTER TABLE follow
+    ALTER COLUMN height DROP DEFAULT,
+    ALTER COLUMN created_at DROP DEFAULT;
+
+-- follow_event is an append-only history of follows and unfollows
+CREATE TABLE follow_event (
+    id BIGSERIAL PRIMARY KEY,
+    follower TEXT NOT NULL,
+    followee TEXT NOT NULL,
+    followed BOOLEAN NOT NULL,
+    height BIGINT NOT NULL,
+    timestamp TIMESTAMP NOT NULL
+);
+
+CREATE INDEX follow_event_followee_idx ON follow_event(followee, timestamp);
+CREATE INDEX follow_event_follower_idx ON follow_event(follower);
+
+INSERT INTO follow_event(follower, followee, followed, height, timestamp)
+SELECT follower, followee, TRUE, height, created_at
+FROM follow;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX follow_follower_created_at_idx;
+DROP INDEX follow_followee_created_at_idx;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+-- indexes are used by followers and following lists
+CREATE INDEX follow_followee_created_at_idx ON follow(followee, created_at DESC, follower DESC);
+CREATE INDEX follow_follower_created_at_idx ON follow(follower, created_at DESC, followee DESC);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP INDEX like_post_liked_at_idx;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- index is used by post likes list
+CREATE INDEX like_post_liked_at_idx ON "like"(post_owner, post_uuid, liked_at DESC, liked_by DESC);
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+-- simple configuration is used since posts are written in different languages
+ALTER TABLE post ADD COLUMN search TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', title), 'A') || setweight(to_tsvector('simple', text), 'B')
+) STORED;
+
+CREATE INDEX post_search_idx ON post USING GIN(search);
+
+CREATE OR REPLACE VIEW calculated_post AS
+SELECT owner, uuid, title, category, preview_image, text, created_at, likes, dislikes, updv, slug, search
+FROM post
+WHERE deleted_at IS NULL;
+
+COMMIT;
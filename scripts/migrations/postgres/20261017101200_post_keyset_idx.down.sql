@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP INDEX post_updv_idx;
+DROP INDEX post_dislikes_idx;
+DROP INDEX post_likes_idx;
+DROP INDEX post_created_at_idx;
+
+CREATE INDEX post_created_at_idx ON post(created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX post_likes_idx ON post(likes DESC) WHERE deleted_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+-- indexes match (sort field, owner, uuid) keyset used by posts list pagination
+DROP INDEX post_created_at_idx;
+DROP INDEX post_likes_idx;
+
+CREATE INDEX post_created_at_idx ON post(created_at, owner, uuid) WHERE deleted_at IS NULL;
+CREATE INDEX post_likes_idx ON post(likes, owner, uuid) WHERE deleted_at IS NULL;
+CREATE INDEX post_dislikes_idx ON post(dislikes, owner, uuid) WHERE deleted_at IS NULL;
+CREATE INDEX post_updv_idx ON post(updv, owner, uuid) WHERE deleted_at IS NULL;
+
+COMMIT;
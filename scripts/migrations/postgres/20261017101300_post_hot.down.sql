@@ -0,0 +1,14 @@
+BEGIN;
+
+DROP VIEW calculated_post;
+
+CREATE VIEW calculated_post AS
+SELECT owner, uuid, title, category, preview_image, text, created_at, likes, dislikes, updv, slug, search
+FROM post
+WHERE deleted_at IS NULL;
+
+DROP INDEX like_event_timestamp_idx;
+
+ALTER TABLE post DROP COLUMN hot;
+
+COMMIT;
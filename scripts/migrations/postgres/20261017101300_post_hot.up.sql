@@ -0,0 +1,19 @@
+BEGIN;
+
+-- hot is a score of net likes decayed by age: every 12.5 hours of age cost the same as 10 times fewer net likes
+ALTER TABLE post ADD COLUMN hot DOUBLE PRECISION GENERATED ALWAYS AS (
+    SIGN((likes - dislikes)::DOUBLE PRECISION) * LOG(GREATEST(ABS(likes - dislikes), 1)::DOUBLE PRECISION) +
+    DATE_PART('epoch', created_at) / 45000
+) STORED;
+
+CREATE INDEX post_hot_idx ON post(hot, owner, uuid) WHERE deleted_at IS NULL;
+
+-- index is used to find posts liked recently for rising sort
+CREATE INDEX like_event_timestamp_idx ON like_event(timestamp);
+
+CREATE OR REPLACE VIEW calculated_post AS
+SELECT owner, uuid, title, category, preview_image, text, created_at, likes, dislikes, updv, slug, search, hot
+FROM post
+WHERE deleted_at IS NULL;
+
+COMMIT;
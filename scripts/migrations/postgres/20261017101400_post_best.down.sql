@@ -0,0 +1,13 @@
+BEGIN;
+
+DROP VIEW calculated_post;
+
+CREATE VIEW calculated_post AS
+SELECT owner, uuid, title, category, preview_image, text, created_at, likes, dislikes, updv, slug, search, hot
+FROM post
+WHERE deleted_at IS NULL;
+
+ALTER TABLE post DROP COLUMN controversial;
+ALTER TABLE post DROP COLUMN best;
+
+COMMIT;
@@ -0,0 +1,27 @@
+BEGIN;
+
+-- best is a lower bound of Wilson score confidence interval (z = 1.96) for a share of likes
+ALTER TABLE post ADD COLUMN best DOUBLE PRECISION GENERATED ALWAYS AS (
+    CASE WHEN likes + dislikes = 0 THEN 0 ELSE (
+        likes::DOUBLE PRECISION / (likes + dislikes) + 1.9208 / (likes + dislikes) -
+        1.96 * SQRT(likes::DOUBLE PRECISION * dislikes / (likes + dislikes) + 0.9604) / (likes + dislikes)
+    ) / (1 + 3.8416 / (likes + dislikes)) END
+) STORED;
+
+-- controversial grows with votes count and is greater when likes and dislikes are balanced
+ALTER TABLE post ADD COLUMN controversial DOUBLE PRECISION GENERATED ALWAYS AS (
+    CASE WHEN likes = 0 OR dislikes = 0 THEN 0 ELSE
+        POWER((likes + dislikes)::DOUBLE PRECISION, LEAST(likes, dislikes)::DOUBLE PRECISION / GREATEST(likes, dislikes))
+    END
+) STORED;
+
+CREATE INDEX post_best_idx ON post(best, owner, uuid) WHERE deleted_at IS NULL;
+CREATE INDEX post_controversial_idx ON post(controversial, owner, uuid) WHERE deleted_at IS NULL;
+
+CREATE OR REPLACE VIEW calculated_post AS
+SELECT owner, uuid, title, category, preview_image, text, created_at, likes, dislikes, updv, slug, search, hot,
+       best, controversial
+FROM post
+WHERE deleted_at IS NULL;
+
+COMMIT;
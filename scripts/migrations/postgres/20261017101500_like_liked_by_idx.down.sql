@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP INDEX like_liked_by_idx;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- index is used to find posts liked by the address and its followees
+CREATE INDEX like_liked_by_idx ON "like"(liked_by);
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+-- timeline contains posts of followees per follower, it's maintained on posts and follows changes
+CREATE TABLE timeline (
+    follower TEXT NOT NULL,
+    post_owner TEXT NOT NULL,
+    post_uuid TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (follower, post_owner, post_uuid),
+    FOREIGN KEY (post_owner, post_uuid) REFERENCES post(owner, uuid) ON DELETE CASCADE
+);
+
+CREATE INDEX timeline_follower_created_at_idx ON timeline(follower, created_at, post_owner, post_uuid);
+CREATE INDEX timeline_post_idx ON timeline(post_owner, post_uuid);
+
+INSERT INTO timeline(follower, post_owner, post_uuid, created_at)
+SELECT follow.follower, post.owner, post.uuid, post.created_at
+FROM follow
+INNER JOIN post ON post.owner = follow.followee AND post.deleted_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP MATERIALIZED VIEW related_post;
+
+COMMIT;
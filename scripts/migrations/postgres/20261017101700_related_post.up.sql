@@ -0,0 +1,33 @@
+BEGIN;
+
+-- related_post contains posts liked by the same addresses that liked the post, up to 20 per post.
+-- It's refreshed concurrently in background, so unique index is required.
+CREATE MATERIALIZED VIEW related_post AS
+WITH liked AS (
+    SELECT post_owner, post_uuid, liked_by FROM "like"
+    INNER JOIN post ON "like".post_owner = post.owner AND "like".post_uuid = post.uuid
+    WHERE "like".weight > 0 AND post.deleted_at IS NULL
+),
+pair AS (
+    SELECT
+        a.post_owner, a.post_uuid, b.post_owner AS related_owner, b.post_uuid AS related_uuid,
+        COUNT(*) AS common_likes
+    FROM liked a
+    INNER JOIN liked b ON a.liked_by = b.liked_by AND (a.post_owner, a.post_uuid) <> (b.post_owner, b.post_uuid)
+    GROUP BY a.post_owner, a.post_uuid, b.post_owner, b.post_uuid
+),
+ranked AS (
+    SELECT
+        pair.*,
+        ROW_NUMBER() OVER (
+            PARTITION BY post_owner, post_uuid ORDER BY common_likes DESC, related_owner, related_uuid
+        ) AS rank
+    FROM pair
+)
+SELECT post_owner, post_uuid, related_owner, related_uuid, common_likes, rank
+FROM ranked
+WHERE rank <= 20;
+
+CREATE UNIQUE INDEX related_post_pk_idx ON related_post(post_owner, post_uuid, related_owner, related_uuid);
+
+COMMIT;
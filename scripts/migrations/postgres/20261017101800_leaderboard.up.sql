@@ -0,0 +1,38 @@
+BEGIN;
+
+-- leaderboard ranks addresses by earned pdv, received likes and created posts over periods ending today.
+-- It's refreshed concurrently in background, so unique index is required.
+CREATE MATERIALIZED VIEW leaderboard AS
+WITH
+period AS (
+    SELECT name, since FROM (VALUES
+        ('day', CURRENT_DATE),
+        ('week', CURRENT_DATE - 6),
+        ('month', CURRENT_DATE - 29),
+        ('all', DATE '-infinity')
+    ) AS p(name, since)
+),
+value AS (
+    SELECT 'pdv' AS metric, period.name AS period, address, SUM(updv)::BIGINT AS value
+    FROM pdv_stats_daily, period
+    WHERE date >= period.since
+    GROUP BY period.name, address
+    UNION ALL
+    SELECT 'likes', period.name, post_owner, COUNT(*)
+    FROM "like", period
+    WHERE weight = 1 AND liked_at >= period.since
+    GROUP BY period.name, post_owner
+    UNION ALL
+    SELECT 'posts', period.name, owner, COUNT(*)
+    FROM post, period
+    WHERE deleted_at IS NULL AND created_at >= period.since
+    GROUP BY period.name, owner
+)
+SELECT metric, period, address, value, RANK() OVER (PARTITION BY metric, period ORDER BY value DESC) AS rank
+FROM value
+WHERE value > 0;
+
+CREATE UNIQUE INDEX leaderboard_pk_idx ON leaderboard(metric, period, address);
+CREATE INDEX leaderboard_rank_idx ON leaderboard(metric, period, rank, address);
+
+COMMIT;
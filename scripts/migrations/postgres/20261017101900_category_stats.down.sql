@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP MATERIALIZED VIEW category_stats_daily;
+DROP MATERIALIZED VIEW category_stats;
+
+COMMIT;
@@ -0,0 +1,26 @@
+BEGIN;
+
+-- category_stats contains posts count, authors posted during the last 30 days and received likes by category.
+-- Views are refreshed concurrently in background, so unique indexes are required.
+CREATE MATERIALIZED VIEW category_stats AS
+SELECT
+    category,
+    COUNT(*) AS posts_count,
+    COUNT(DISTINCT owner) FILTER (WHERE created_at >= CURRENT_DATE - 29) AS active_authors,
+    SUM(likes) AS likes
+FROM post
+WHERE deleted_at IS NULL
+GROUP BY category;
+
+CREATE UNIQUE INDEX category_stats_category_idx ON category_stats(category);
+
+-- category_stats_daily contains count of posts created by date
+CREATE MATERIALIZED VIEW category_stats_daily AS
+SELECT category, created_at::DATE AS date, COUNT(*) AS posts_count
+FROM post
+WHERE deleted_at IS NULL
+GROUP BY category, created_at::DATE;
+
+CREATE UNIQUE INDEX category_stats_daily_pk_idx ON category_stats_daily(category, date);
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+-- journal_range keeps heights ranges saved to the messages journal, adjacent ranges are merged,
+-- so the journal covers the chain history when there is one range starting at genesis (zero height).
+-- It isn't backfilled: blocks processed before the journal was introduced can't be replayed.
+CREATE TABLE journal_range (
+    from_height BIGINT NOT NULL,
+    to_height BIGINT NOT NULL,
+
+    PRIMARY KEY (from_height),
+    CHECK (from_height <= to_height)
+);
+
+COMMIT;
@@ -0,0 +1,37 @@
+BEGIN;
+
+CREATE OR REPLACE FUNCTION pdv_balance()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF (TG_OP = 'DELETE') THEN
+        PERFORM add_balance(OLD.address, 'updv', OLD.timestamp::DATE, -OLD.updv);
+    ELSE
+        PERFORM add_balance(NEW.address, 'updv', NEW.timestamp::DATE, NEW.updv);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE 'plpgsql';
+
+WITH l AS (
+    SELECT address, timestamp::DATE AS date, SUM(updv) AS updv
+    FROM updv
+    WHERE source = 'like'
+    GROUP BY address, timestamp::DATE
+)
+INSERT INTO balance_daily(address, denom, date, amount)
+SELECT address, 'updv', date, updv FROM l
+ON CONFLICT (address, denom, date) DO UPDATE SET amount = balance_daily.amount + excluded.amount;
+
+WITH l AS (
+    SELECT address, SUM(updv) AS updv
+    FROM updv
+    WHERE source = 'like'
+    GROUP BY address
+)
+INSERT INTO balance(address, denom, amount)
+SELECT address, 'updv', updv FROM l
+ON CONFLICT (address, denom) DO UPDATE SET amount = balance.amount + excluded.amount;
+
+COMMIT;
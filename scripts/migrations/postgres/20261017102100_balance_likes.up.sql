@@ -0,0 +1,40 @@
+BEGIN;
+
+-- pdv of likes isn't a token, so updv balance is genesis + rewards only
+CREATE OR REPLACE FUNCTION pdv_balance()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF (TG_OP = 'DELETE') THEN
+        IF (OLD.source <> 'like') THEN
+            PERFORM add_balance(OLD.address, 'updv', OLD.timestamp::DATE, -OLD.updv);
+        END IF;
+    ELSIF (NEW.source <> 'like') THEN
+        PERFORM add_balance(NEW.address, 'updv', NEW.timestamp::DATE, NEW.updv);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE 'plpgsql';
+
+WITH l AS (
+    SELECT address, timestamp::DATE AS date, SUM(updv) AS updv
+    FROM updv
+    WHERE source = 'like'
+    GROUP BY address, timestamp::DATE
+)
+UPDATE balance_daily SET amount = balance_daily.amount - l.updv
+FROM l
+WHERE balance_daily.address = l.address AND balance_daily.denom = 'updv' AND balance_daily.date = l.date;
+
+WITH l AS (
+    SELECT address, SUM(updv) AS updv
+    FROM updv
+    WHERE source = 'like'
+    GROUP BY address
+)
+UPDATE balance SET amount = balance.amount - l.updv
+FROM l
+WHERE balance.address = l.address AND balance.denom = 'updv';
+
+COMMIT;
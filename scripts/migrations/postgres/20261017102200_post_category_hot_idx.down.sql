@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP INDEX post_category_hot_idx;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- index is used to find the hottest posts of the category for feed
+CREATE INDEX post_category_hot_idx ON post(category, hot DESC) WHERE deleted_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX leaderboard_value_idx;
+CREATE INDEX leaderboard_rank_idx ON leaderboard(metric, period, rank, address);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+-- leaderboard is paginated by values, because ranks are recomputed by every refresh
+DROP INDEX leaderboard_rank_idx;
+CREATE INDEX leaderboard_value_idx ON leaderboard(metric, period, value, address);
+
+COMMIT;